@@ -1,3 +0,0 @@
-export async function register() {
-  // No-op — reserved for future instrumentation
-}
@@ -0,0 +1,66 @@
+#!/usr/bin/env tsx
+/**
+ * Validates workflow graphs and reports every problem in one pass.
+ * With file arguments, checks exported workflow JSON files; otherwise
+ * checks every workflow stored in the database.
+ *
+ * Usage: pnpm workflows:validate [file.json ...]
+ */
+
+import { readFileSync } from 'fs';
+import postgres from 'postgres';
+import { validateWorkflows, type WorkflowSource } from '../src/lib/engine/validate';
+
+function loadFiles(paths: string[]): WorkflowSource[] {
+  return paths.map(path => {
+    const doc = JSON.parse(readFileSync(path, 'utf-8'));
+    return { name: doc.name || path, source: path, graphData: doc.graphData ?? doc };
+  });
+}
+
+async function loadDatabase(): Promise<WorkflowSource[]> {
+  const url = process.env.DATABASE_URL;
+  if (!url) {
+    console.error('DATABASE_URL is not set');
+    process.exit(1);
+  }
+
+  const sql = postgres(url, { prepare: false, connect_timeout: 10 });
+  try {
+    const rows = await sql`SELECT id, name, graph_data FROM cadre.workflows ORDER BY name`;
+    return rows.map(r => ({ name: r.name, source: r.id, graphData: r.graph_data }));
+  } finally {
+    await sql.end();
+  }
+}
+
+async function main() {
+  const paths = process.argv.slice(2);
+  let workflows: WorkflowSource[];
+  try {
+    workflows = paths.length > 0 ? loadFiles(paths) : await loadDatabase();
+  } catch (err) {
+    console.error('Failed to load workflows:', err instanceof Error ? err.message : err);
+    process.exit(1);
+  }
+
+  const results = validateWorkflows(workflows);
+  let failed = 0;
+
+  for (const result of results) {
+    if (result.errors.length === 0) {
+      console.log(`ok    ${result.name} (${result.source})`);
+      continue;
+    }
+    failed++;
+    console.log(`FAIL  ${result.name} (${result.source})`);
+    for (const error of result.errors) {
+      console.log(`        - ${error}`);
+    }
+  }
+
+  console.log(`\n${results.length - failed}/${results.length} workflows valid`);
+  if (failed > 0) process.exit(1);
+}
+
+main();
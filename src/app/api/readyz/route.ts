@@ -0,0 +1,39 @@
+import { NextResponse } from 'next/server';
+import { sql } from 'drizzle-orm';
+import { db } from '@/lib/db';
+import { isDraining } from '@/lib/engine/active-runs';
+import { listProviders } from '@/lib/providers/registry';
+import { cachedCheck, runReadinessChecks } from '@/lib/readiness';
+
+export const dynamic = 'force-dynamic';
+
+// Spawning every CLI per probe is too costly at kubelet frequency
+const checkProviders = cachedCheck(async () => {
+  const providers = listProviders();
+  const usable = await Promise.all(providers.map(p => p.validateCli().catch(() => false)));
+  if (!usable.some(Boolean)) {
+    throw new Error(`No provider CLI available (tried ${providers.map(p => p.id).join(', ')})`);
+  }
+});
+
+/**
+ * Readiness probe: 503 with the failing checks named until the database
+ * answers a query and at least one provider CLI is usable, and again once
+ * the server starts draining. /api/health is left as the liveness probe.
+ */
+export async function GET() {
+  const report = await runReadinessChecks({
+    database: async () => {
+      await db.execute(sql`select 1`);
+    },
+    providers: checkProviders,
+    shutdown: async () => {
+      if (isDraining()) throw new Error('Server is shutting down');
+    },
+  });
+
+  return NextResponse.json(
+    { status: report.ready ? 'ready' : 'not_ready', timestamp: new Date().toISOString(), checks: report.checks },
+    { status: report.ready ? 200 : 503 }
+  );
+}
@@ -0,0 +1,36 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs } from '@/lib/db/schema';
+import { eq, and } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { handleApiError } from '@/lib/api-error';
+import { parseUuid } from '@/lib/validation';
+import { collectArtifacts } from '@/lib/engine/artifacts';
+import type { NodeRunState } from '@/lib/engine/types';
+
+export async function GET(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  try {
+    const userId = await getAuthUserId();
+    const { id } = await params;
+
+    const check = parseUuid(id, 'run ID');
+    if (!check.success) return check.response;
+
+    const [run] = await db
+      .select({ nodeStates: runs.nodeStates })
+      .from(runs)
+      .where(and(eq(runs.id, id), eq(runs.userId, userId)));
+
+    if (!run) {
+      return NextResponse.json({ error: 'Run not found' }, { status: 404 });
+    }
+
+    const artifacts = collectArtifacts((run.nodeStates || {}) as Record<string, NodeRunState>);
+    return NextResponse.json({ artifacts });
+  } catch (error) {
+    return handleApiError(error, 'GET /api/runs/:id/artifacts');
+  }
+}
@@ -0,0 +1,121 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs, runEvents } from '@/lib/db/schema';
+import { eq, and, gt, asc } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { getConfig } from '@/lib/config';
+import { createSseWriter, type SseWriter } from '@/lib/sse';
+import { parseQuery, parseUuid, runEventsQuerySchema } from '@/lib/validation';
+
+export const dynamic = 'force-dynamic';
+
+const TERMINAL_STATUSES = ['completed', 'failed', 'cancelled'];
+
+function loadEvents(runId: string, afterSeq: number) {
+  return db
+    .select({
+      seq: runEvents.seq,
+      type: runEvents.type,
+      nodeId: runEvents.nodeId,
+      data: runEvents.data,
+      timestamp: runEvents.createdAt,
+    })
+    .from(runEvents)
+    .where(and(eq(runEvents.runId, runId), gt(runEvents.seq, afterSeq)))
+    .orderBy(asc(runEvents.seq));
+}
+
+export async function GET(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`events:${userId}`, 30);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const { id } = await params;
+
+    const check = parseUuid(id, 'run ID');
+    if (!check.success) return check.response;
+
+    const parsed = parseQuery(runEventsQuerySchema, request.nextUrl.searchParams);
+    if (!parsed.success) return parsed.response;
+
+    const [run] = await db
+      .select({ status: runs.status })
+      .from(runs)
+      .where(and(eq(runs.id, id), eq(runs.userId, userId)));
+
+    if (!run) {
+      return NextResponse.json({ error: 'Run not found' }, { status: 404 });
+    }
+
+    if (!parsed.data.follow) {
+      return NextResponse.json({ events: await loadEvents(id, -1) });
+    }
+
+    const abortSignal = request.signal;
+    let writer: SseWriter<'event' | 'done' | 'error'> | undefined;
+
+    const stream = new ReadableStream<Uint8Array>({
+      async start(controller) {
+        const sse = createSseWriter<'event' | 'done' | 'error'>(controller, null, getConfig().app.sseKeepaliveMs);
+        writer = sse;
+        let lastSeq = -1;
+
+        // Same 10 minute ceiling as the run state stream
+        for (let poll = 0; poll < 600 && !abortSignal.aborted && !sse.closed; poll++) {
+          try {
+            // Read status first so events written just before completion are still picked up
+            const [current] = await db.select({ status: runs.status }).from(runs).where(eq(runs.id, id));
+            const events = await loadEvents(id, lastSeq);
+            for (const event of events) {
+              sse.send('event', event);
+              lastSeq = event.seq;
+            }
+
+            if (!current || TERMINAL_STATUSES.includes(current.status)) {
+              sse.send('done', { status: current?.status ?? 'deleted' });
+              break;
+            }
+          } catch {
+            if (!abortSignal.aborted) sse.send('error', { message: 'Internal error' });
+            break;
+          }
+
+          await new Promise<void>((resolve) => {
+            const timer = setTimeout(done, 1000);
+            function done() {
+              clearTimeout(timer);
+              abortSignal.removeEventListener('abort', done);
+              resolve();
+            }
+            abortSignal.addEventListener('abort', done, { once: true });
+          });
+        }
+
+        sse.close();
+      },
+      cancel() {
+        writer?.close();
+      },
+    });
+
+    return new Response(stream, {
+      headers: {
+        'Content-Type': 'text/event-stream',
+        'Cache-Control': 'no-cache, no-transform',
+        Connection: 'keep-alive',
+        'X-Accel-Buffering': 'no',
+      },
+    });
+  } catch (error) {
+    return handleApiError(error, 'GET /api/runs/:id/events');
+  }
+}
@@ -5,8 +5,9 @@ import { eq, and } from 'drizzle-orm';
 import { getAuthUserId } from '@/lib/api-auth';
 import { handleApiError } from '@/lib/api-error';
 import { parseUuid } from '@/lib/validation';
+import { resolveWithin } from '@/lib/paths';
 import { readdirSync, readFileSync, statSync } from 'fs';
-import { join, relative, resolve, normalize } from 'path';
+import { join, relative } from 'path';
 
 interface FileEntry {
   path: string;
@@ -68,9 +69,9 @@ export async function GET(
 
     if (filePath) {
       // Return file contents
-      const resolved = resolve(workspacePath, normalize(filePath));
-      // Prevent path traversal
-      if (!resolved.startsWith(workspacePath)) {
+      // Prevent path traversal, including via symlinks
+      const resolved = resolveWithin(workspacePath, filePath);
+      if (!resolved) {
         return NextResponse.json({ error: 'Invalid file path' }, { status: 400 });
       }
 
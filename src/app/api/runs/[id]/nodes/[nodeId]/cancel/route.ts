@@ -0,0 +1,51 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs } from '@/lib/db/schema';
+import { eq, and } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { parseUuid } from '@/lib/validation';
+import { getActiveRun } from '@/lib/engine/active-runs';
+
+export async function POST(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string; nodeId: string }> }
+) {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`cancel-node:${userId}`, 30);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const { id, nodeId } = await params;
+
+    const check = parseUuid(id, 'run ID');
+    if (!check.success) return check.response;
+
+    const [run] = await db
+      .select({ status: runs.status })
+      .from(runs)
+      .where(and(eq(runs.id, id), eq(runs.userId, userId)));
+
+    if (!run) {
+      return NextResponse.json({ error: 'Run not found' }, { status: 404 });
+    }
+
+    // Cancelling needs the live executor, as with pause
+    const active = getActiveRun(id);
+    if (!active) {
+      return NextResponse.json({ error: 'Run is not active on this server' }, { status: 409 });
+    }
+
+    if (!active.executor.cancelNode(nodeId)) {
+      return NextResponse.json({ error: 'Node is not running' }, { status: 409 });
+    }
+
+    return NextResponse.json({ success: true, nodeId });
+  } catch (error) {
+    return handleApiError(error, 'POST /api/runs/:id/nodes/:nodeId/cancel');
+  }
+}
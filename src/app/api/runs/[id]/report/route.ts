@@ -0,0 +1,72 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs, workflows } from '@/lib/db/schema';
+import { eq, and } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { parseQuery, parseUuid, runReportQuerySchema } from '@/lib/validation';
+import { buildRunReport, renderReportMarkdown } from '@/lib/engine/report';
+import type { NodeRunState, RunState, WorkflowNode } from '@/lib/engine/types';
+
+export async function GET(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`report:${userId}`, 30);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const { id } = await params;
+
+    const check = parseUuid(id, 'run ID');
+    if (!check.success) return check.response;
+
+    const parsed = parseQuery(runReportQuerySchema, request.nextUrl.searchParams);
+    if (!parsed.success) return parsed.response;
+    const { format } = parsed.data;
+
+    const [row] = await db
+      .select({ run: runs, workflowName: workflows.name, graphData: workflows.graphData })
+      .from(runs)
+      .innerJoin(workflows, eq(runs.workflowId, workflows.id))
+      .where(and(eq(runs.id, id), eq(runs.userId, userId)));
+
+    if (!row) {
+      return NextResponse.json({ error: 'Run not found' }, { status: 404 });
+    }
+
+    const { run } = row;
+    const graphData = row.graphData as { nodes?: WorkflowNode[] } | null;
+
+    const report = buildRunReport({
+      id: run.id,
+      workflowId: run.workflowId,
+      workflowName: row.workflowName,
+      status: run.status,
+      startedAt: run.startedAt,
+      completedAt: run.completedAt,
+      context: run.context as Record<string, unknown> | null,
+      nodeStates: run.nodeStates as Record<string, NodeRunState> | null,
+      tokenUsage: run.tokenUsage as RunState['totalTokens'] | null,
+      nodes: graphData?.nodes,
+    });
+
+    if (format === 'md') {
+      return new NextResponse(renderReportMarkdown(report), {
+        headers: {
+          'Content-Type': 'text/markdown; charset=utf-8',
+          'Content-Disposition': `inline; filename="run-${run.id.slice(0, 8)}-report.md"`,
+        },
+      });
+    }
+
+    return NextResponse.json(report);
+  } catch (error) {
+    return handleApiError(error, 'GET /api/runs/:id/report');
+  }
+}
@@ -5,7 +5,8 @@ import { eq, and } from 'drizzle-orm';
 import { getAuthUserId } from '@/lib/api-auth';
 import { rateLimit } from '@/lib/rate-limit';
 import { handleApiError } from '@/lib/api-error';
-import { parseBody, parseUuid, cancelRunSchema } from '@/lib/validation';
+import { parseBody, parseUuid, runActionSchema } from '@/lib/validation';
+import { getActiveRun } from '@/lib/engine/active-runs';
 
 export async function GET(
   request: NextRequest,
@@ -46,8 +47,9 @@ export async function PATCH(
 
     const body = await request.json();
 
-    const parsed = parseBody(cancelRunSchema, body);
+    const parsed = parseBody(runActionSchema, body);
     if (!parsed.success) return parsed.response;
+    const { action } = parsed.data;
 
     const [run] = await db
       .select()
@@ -62,6 +64,27 @@ export async function PATCH(
       return NextResponse.json({ error: 'Run is already finished' }, { status: 400 });
     }
 
+    const active = getActiveRun(id);
+
+    if (action === 'pause' || action === 'resume') {
+      // Pausing needs the live executor; runs from before a restart can't be held
+      if (!active) {
+        return NextResponse.json({ error: 'Run is not active on this server' }, { status: 409 });
+      }
+      if (action === 'pause') active.executor.pause();
+      else active.executor.resume();
+
+      const [updated] = await db
+        .update(runs)
+        .set({ status: action === 'pause' ? 'paused' : 'running' })
+        .where(and(eq(runs.id, id), eq(runs.userId, userId)))
+        .returning();
+
+      return NextResponse.json(updated);
+    }
+
+    active?.executor.abort();
+
     const [updated] = await db
       .update(runs)
       .set({ status: 'cancelled', completedAt: new Date() })
@@ -100,8 +123,9 @@ export async function DELETE(
       return NextResponse.json({ error: 'Run not found' }, { status: 404 });
     }
 
-    if (run.status === 'running') {
-      return NextResponse.json({ error: 'Cannot delete a running run' }, { status: 400 });
+    // An active executor would keep its concurrency slot after the row is gone
+    if (['running', 'paused', 'queued'].includes(run.status)) {
+      return NextResponse.json({ error: `Cannot delete a ${run.status} run; cancel it first` }, { status: 400 });
     }
 
     await db.delete(runs).where(and(eq(runs.id, id), eq(runs.userId, userId)));
@@ -5,6 +5,10 @@ import { eq, and } from 'drizzle-orm';
 import { getAuthUserId } from '@/lib/api-auth';
 import { rateLimit } from '@/lib/rate-limit';
 import { parseUuid } from '@/lib/validation';
+import { isDraining } from '@/lib/engine/active-runs';
+import { apiError } from '@/lib/api-error';
+import { getConfig } from '@/lib/config';
+import { createSseWriter, parseEventTypeFilter, STREAM_EVENT_TYPES, type SseWriter } from '@/lib/sse';
 
 export const dynamic = 'force-dynamic';
 
@@ -24,29 +28,36 @@ export async function GET(
   const check = parseUuid(id, 'run ID');
   if (!check.success) return check.response;
 
-  const encoder = new TextEncoder();
+  const filter = parseEventTypeFilter(request.nextUrl.searchParams);
+  if (filter.unknown.length > 0) {
+    return apiError(`Unknown event types: ${filter.unknown.join(', ')}`, 400, { allowed: STREAM_EVENT_TYPES });
+  }
+
   const abortSignal = request.signal;
+  let writer: SseWriter | undefined;
 
-  const stream = new ReadableStream({
+  const stream = new ReadableStream<Uint8Array>({
     async start(controller) {
-      const sendEvent = (event: string, data: unknown) => {
-        try {
-          controller.enqueue(encoder.encode(`event: ${event}\ndata: ${JSON.stringify(data)}\n\n`));
-        } catch {
-          // Controller already closed
-        }
-      };
+      const sse = createSseWriter(controller, filter.types, getConfig().app.sseKeepaliveMs);
+      writer = sse;
+      const sendEvent = sse.send;
 
       // Send initial heartbeat
       sendEvent('heartbeat', { time: Date.now() });
 
       let completed = false;
+      let lastState = '';
       let pollCount = 0;
       const maxPolls = 600; // 10 minutes at 1s intervals
 
       while (!completed && pollCount < maxPolls) {
-        // Check if client disconnected
-        if (abortSignal.aborted) {
+        // Check if client disconnected, either via the request or a failed write
+        if (abortSignal.aborted || sse.closed) {
+          break;
+        }
+
+        if (isDraining()) {
+          sendEvent('error', { message: 'Server is shutting down' });
           break;
         }
 
@@ -61,11 +72,13 @@ export async function GET(
             break;
           }
 
-          sendEvent('state', {
-            status: run.status,
-            nodeStates: run.nodeStates,
-            tokenUsage: run.tokenUsage,
-          });
+          // Only send state when it changed; keepalive comments cover the idle gaps
+          const state = { status: run.status, nodeStates: run.nodeStates, tokenUsage: run.tokenUsage };
+          const serialized = JSON.stringify(state);
+          if (serialized !== lastState) {
+            lastState = serialized;
+            sendEvent('state', state);
+          }
 
           if (['completed', 'failed', 'cancelled'].includes(run.status)) {
             sendEvent('done', { status: run.status });
@@ -97,11 +110,10 @@ export async function GET(
         }
       }
 
-      try {
-        controller.close();
-      } catch {
-        // Already closed
-      }
+      sse.close();
+    },
+    cancel() {
+      writer?.close();
     },
   });
 
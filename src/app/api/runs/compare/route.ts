@@ -0,0 +1,59 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs, workflows } from '@/lib/db/schema';
+import { eq, and, inArray } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { parseQuery, compareRunsQuerySchema } from '@/lib/validation';
+import { buildRunReport, compareReports } from '@/lib/engine/report';
+import type { NodeRunState, RunState, WorkflowNode } from '@/lib/engine/types';
+
+export async function GET(request: NextRequest) {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`compare-runs:${userId}`, 30);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const parsed = parseQuery(compareRunsQuerySchema, request.nextUrl.searchParams);
+    if (!parsed.success) return parsed.response;
+    const { a, b } = parsed.data;
+
+    const rows = await db
+      .select({ run: runs, workflowName: workflows.name, graphData: workflows.graphData })
+      .from(runs)
+      .innerJoin(workflows, eq(runs.workflowId, workflows.id))
+      .where(and(inArray(runs.id, [a, b]), eq(runs.userId, userId)));
+
+    const rowA = rows.find(r => r.run.id === a);
+    const rowB = rows.find(r => r.run.id === b);
+    if (!rowA || !rowB) {
+      return NextResponse.json({ error: 'Run not found' }, { status: 404 });
+    }
+    if (rowA.run.workflowId !== rowB.run.workflowId) {
+      return NextResponse.json({ error: 'Runs belong to different workflows' }, { status: 400 });
+    }
+
+    const [reportA, reportB] = [rowA, rowB].map(({ run, workflowName, graphData }) =>
+      buildRunReport({
+        id: run.id,
+        workflowId: run.workflowId,
+        workflowName,
+        status: run.status,
+        startedAt: run.startedAt,
+        completedAt: run.completedAt,
+        context: run.context as Record<string, unknown> | null,
+        nodeStates: run.nodeStates as Record<string, NodeRunState> | null,
+        tokenUsage: run.tokenUsage as RunState['totalTokens'] | null,
+        nodes: (graphData as { nodes?: WorkflowNode[] } | null)?.nodes,
+      })
+    );
+
+    return NextResponse.json(compareReports(reportA, reportB));
+  } catch (error) {
+    return handleApiError(error, 'GET /api/runs/compare');
+  }
+}
@@ -0,0 +1,43 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs } from '@/lib/db/schema';
+import { eq, and } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { parseUuid } from '@/lib/validation';
+
+// Takes a run out of the queue; it is kept as cancelled, like runs cancelled with PATCH /api/runs/:id
+export async function DELETE(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`queue:${userId}`, 30);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const { id } = await params;
+
+    const check = parseUuid(id, 'run ID');
+    if (!check.success) return check.response;
+
+    // Only matches while still queued, so a run the dispatcher just started is left alone
+    const [cancelled] = await db
+      .update(runs)
+      .set({ status: 'cancelled', completedAt: new Date() })
+      .where(and(eq(runs.id, id), eq(runs.userId, userId), eq(runs.status, 'queued')))
+      .returning({ id: runs.id });
+
+    if (!cancelled) {
+      return NextResponse.json({ error: 'Queued run not found' }, { status: 404 });
+    }
+
+    return NextResponse.json({ success: true });
+  } catch (error) {
+    return handleApiError(error, 'DELETE /api/runs/queue/:id');
+  }
+}
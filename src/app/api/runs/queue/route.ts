@@ -0,0 +1,51 @@
+import { NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { runs } from '@/lib/db/schema';
+import { eq } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { orderQueue } from '@/lib/engine/run-queue';
+
+export const dynamic = 'force-dynamic';
+
+export async function GET() {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`queue:${userId}`, 30);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const queued = await db
+      .select({
+        id: runs.id,
+        workflowId: runs.workflowId,
+        userId: runs.userId,
+        priority: runs.priority,
+        labels: runs.labels,
+        startedAt: runs.startedAt,
+      })
+      .from(runs)
+      .where(eq(runs.status, 'queued'));
+
+    // Positions count every queued run, since the queue is shared by all users
+    const queue = orderQueue(queued).flatMap((run, index) =>
+      run.userId === userId
+        ? [{
+            id: run.id,
+            workflowId: run.workflowId,
+            priority: run.priority,
+            labels: run.labels,
+            queuedAt: run.startedAt,
+            position: index + 1,
+          }]
+        : []
+    );
+
+    return NextResponse.json({ queue });
+  } catch (error) {
+    return handleApiError(error, 'GET /api/runs/queue');
+  }
+}
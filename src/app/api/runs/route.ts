@@ -6,6 +6,7 @@ import { getAuthUserId } from '@/lib/api-auth';
 import { rateLimit } from '@/lib/rate-limit';
 import { handleApiError } from '@/lib/api-error';
 import { parseQuery, listRunsQuerySchema } from '@/lib/validation';
+import { runLabelCondition } from '@/lib/run-filters';
 
 export async function GET(request: NextRequest) {
   try {
@@ -18,7 +19,7 @@ export async function GET(request: NextRequest) {
 
     const parsed = parseQuery(listRunsQuerySchema, request.nextUrl.searchParams);
     if (!parsed.success) return parsed.response;
-    const { workflowId, status, limit, offset } = parsed.data;
+    const { workflowId, status, label, limit, offset } = parsed.data;
 
     const conditions = [eq(runs.userId, userId)];
     if (workflowId) {
@@ -27,6 +28,9 @@ export async function GET(request: NextRequest) {
     if (status) {
       conditions.push(eq(runs.status, status));
     }
+    if (label) {
+      conditions.push(runLabelCondition(label));
+    }
 
     const allRuns = await db
       .select({
@@ -34,6 +38,7 @@ export async function GET(request: NextRequest) {
         workflowId: runs.workflowId,
         status: runs.status,
         tokenUsage: runs.tokenUsage,
+        labels: runs.labels,
         startedAt: runs.startedAt,
         completedAt: runs.completedAt,
       })
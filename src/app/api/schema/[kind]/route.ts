@@ -0,0 +1,27 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { getAuthUserId } from '@/lib/api-auth';
+import { handleApiError } from '@/lib/api-error';
+import { getJsonSchema, isSchemaKind, SCHEMA_KINDS } from '@/lib/workflow-schema';
+
+export async function GET(
+  request: NextRequest,
+  { params }: { params: Promise<{ kind: string }> }
+) {
+  try {
+    await getAuthUserId();
+
+    const { kind } = await params;
+    if (!isSchemaKind(kind)) {
+      return NextResponse.json(
+        { error: `Unknown schema kind. Expected one of: ${SCHEMA_KINDS.join(', ')}` },
+        { status: 404 }
+      );
+    }
+
+    return NextResponse.json(getJsonSchema(kind), {
+      headers: { 'Content-Type': 'application/schema+json' },
+    });
+  } catch (error) {
+    return handleApiError(error, 'GET /api/schema/:kind');
+  }
+}
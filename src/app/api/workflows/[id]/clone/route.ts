@@ -0,0 +1,68 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { workflows } from '@/lib/db/schema';
+import { eq, and, like } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { handleApiError } from '@/lib/api-error';
+import { cloneWorkflowSchema, parseBody, parseUuid } from '@/lib/validation';
+import { copyNamePattern, nextCopyName } from '@/lib/workflow-names';
+
+export async function POST(
+  request: NextRequest,
+  { params }: { params: Promise<{ id: string }> }
+) {
+  try {
+    const userId = await getAuthUserId();
+    const { id } = await params;
+
+    const check = parseUuid(id, 'workflow ID');
+    if (!check.success) return check.response;
+
+    const text = await request.text();
+    const parsed = parseBody(cloneWorkflowSchema, text ? JSON.parse(text) : {});
+    if (!parsed.success) return parsed.response;
+    const { name, description, variables } = parsed.data;
+
+    const [source] = await db
+      .select()
+      .from(workflows)
+      .where(and(eq(workflows.id, id), eq(workflows.userId, userId)));
+
+    if (!source) {
+      return NextResponse.json({ error: 'Workflow not found' }, { status: 404 });
+    }
+
+    let targetName = name;
+    if (targetName) {
+      const [existing] = await db
+        .select({ id: workflows.id })
+        .from(workflows)
+        .where(and(eq(workflows.userId, userId), eq(workflows.name, targetName)));
+      if (existing) {
+        return NextResponse.json({ error: `A workflow named "${targetName}" already exists` }, { status: 409 });
+      }
+    } else {
+      const taken = await db
+        .select({ name: workflows.name })
+        .from(workflows)
+        .where(and(eq(workflows.userId, userId), like(workflows.name, copyNamePattern(source.name))));
+      targetName = nextCopyName(source.name, taken.map(w => w.name));
+    }
+
+    const [clone] = await db
+      .insert(workflows)
+      .values({
+        userId,
+        name: targetName,
+        description: description ?? source.description,
+        graphData: source.graphData,
+        variables: { ...(source.variables as Record<string, string>), ...variables },
+        timeout: source.timeout,
+      })
+      .returning();
+
+    return NextResponse.json(clone, { status: 201 });
+  } catch (error) {
+    return handleApiError(error, 'POST /api/workflows/:id/clone');
+  }
+}
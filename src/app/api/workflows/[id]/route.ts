@@ -3,7 +3,8 @@ import { db } from '@/lib/db';
 import { workflows, runs } from '@/lib/db/schema';
 import { eq, and } from 'drizzle-orm';
 import { getAuthUserId } from '@/lib/api-auth';
-import { handleApiError } from '@/lib/api-error';
+import { apiError, handleApiError } from '@/lib/api-error';
+import { findUnknownProviders } from '@/lib/engine/validate';
 import { parseBody, parseUuid, updateWorkflowSchema } from '@/lib/validation';
 
 export async function GET(
@@ -47,7 +48,12 @@ export async function PUT(
 
     const parsed = parseBody(updateWorkflowSchema, body);
     if (!parsed.success) return parsed.response;
-    const { name, description, graphData, variables } = parsed.data;
+    const { name, description, graphData, variables, timeout } = parsed.data;
+
+    const providerErrors = findUnknownProviders(graphData);
+    if (providerErrors.length > 0) {
+      return apiError('Validation failed', 400, { graphData: providerErrors });
+    }
 
     // Only update fields that are provided
     const updateData: Record<string, unknown> = { updatedAt: new Date() };
@@ -55,6 +61,7 @@ export async function PUT(
     if (description !== undefined) updateData.description = description;
     if (graphData !== undefined) updateData.graphData = graphData;
     if (variables !== undefined) updateData.variables = variables;
+    if (timeout !== undefined) updateData.timeout = timeout;
 
     const [updated] = await db
       .update(workflows)
@@ -1,9 +1,14 @@
 import { NextRequest, NextResponse } from 'next/server';
 import { getAuthUserId } from '@/lib/api-auth';
 import { rateLimit } from '@/lib/rate-limit';
-import { handleApiError } from '@/lib/api-error';
-import { parseUuid } from '@/lib/validation';
+import { apiError, handleApiError } from '@/lib/api-error';
+import { idempotencyKeySchema, parseBody, parseUuid, startRunSchema } from '@/lib/validation';
 import { startWorkflowRun } from '@/lib/engine/run-simple';
+import { isDraining, RunCapacityError } from '@/lib/engine/active-runs';
+import { RunQueueFullError } from '@/lib/engine/run-queue';
+import { withIdempotency } from '@/lib/idempotency';
+import { SecretError } from '@/lib/secrets';
+import { InputValidationError } from '@/lib/engine/inputs';
 
 export async function POST(
   request: NextRequest,
@@ -21,10 +26,52 @@ export async function POST(
     const check = parseUuid(id, 'workflow ID');
     if (!check.success) return check.response;
 
-    const { runId, status } = await startWorkflowRun(id, userId);
+    const text = await request.text();
+    const parsed = parseBody(startRunSchema, text ? JSON.parse(text) : {});
+    if (!parsed.success) return parsed.response;
 
-    return NextResponse.json({ runId, status }, { status: 202 });
+    const headerKey = request.headers.get('idempotency-key');
+    if (headerKey !== null) {
+      const keyCheck = parseBody(idempotencyKeySchema, headerKey);
+      if (!keyCheck.success) return keyCheck.response;
+    }
+    const idempotencyKey = headerKey ?? parsed.data.idempotencyKey;
+    const options = {
+      inputs: parsed.data.inputs,
+      labels: parsed.data.labels,
+      overrides: parsed.data.overrides,
+      priority: parsed.data.priority,
+    };
+
+    if (isDraining()) {
+      return NextResponse.json({ error: 'Server is shutting down' }, { status: 503 });
+    }
+
+    if (!idempotencyKey) {
+      const { runId, status } = await startWorkflowRun(id, userId, options);
+      return NextResponse.json({ runId, status }, { status: 202 });
+    }
+
+    const { value, replayed } = await withIdempotency(
+      `${userId}:${id}:${idempotencyKey}`,
+      () => startWorkflowRun(id, userId, options)
+    );
+
+    return NextResponse.json(
+      { runId: value.runId, status: value.status },
+      { status: 202, headers: replayed ? { 'Idempotent-Replayed': 'true' } : undefined }
+    );
   } catch (error) {
+    // The message names the limit that was hit: concurrent runs or the queue
+    if (error instanceof RunCapacityError || error instanceof RunQueueFullError) {
+      return NextResponse.json({ error: error.message }, { status: 429, headers: { 'Retry-After': '10' } });
+    }
+    if (error instanceof InputValidationError) {
+      return apiError('Invalid inputs', 400, { inputs: error.errors });
+    }
+    if (error instanceof SecretError) {
+      return NextResponse.json({ error: error.message }, { status: 400 });
+    }
     return handleApiError(error, 'POST /api/workflows/:id/run');
   }
 }
@@ -0,0 +1,67 @@
+import { NextRequest, NextResponse } from 'next/server';
+import { db } from '@/lib/db';
+import { workflows } from '@/lib/db/schema';
+import { eq, and, inArray } from 'drizzle-orm';
+import { getAuthUserId } from '@/lib/api-auth';
+import { rateLimit } from '@/lib/rate-limit';
+import { handleApiError } from '@/lib/api-error';
+import { logger } from '@/lib/logger';
+import { parseBody, importWorkflowsSchema } from '@/lib/validation';
+import { checkImportItems, findNameConflicts } from '@/lib/workflow-import';
+
+export async function POST(request: NextRequest) {
+  try {
+    const userId = await getAuthUserId();
+
+    const rl = rateLimit(`import-workflows:${userId}`, 10);
+    if (!rl.success) {
+      return NextResponse.json({ error: 'Too many requests' }, { status: 429 });
+    }
+
+    const body = await request.json();
+    const parsed = parseBody(importWorkflowsSchema, body);
+    if (!parsed.success) return parsed.response;
+
+    const { valid, results } = checkImportItems(parsed.data.workflows);
+
+    if (!parsed.data.force && valid.length > 0) {
+      const names = valid.map(v => v.data.name);
+      const existing = await db
+        .select({ name: workflows.name })
+        .from(workflows)
+        .where(and(eq(workflows.userId, userId), inArray(workflows.name, names)));
+      const conflicts = findNameConflicts(names, existing.map(w => w.name));
+      if (conflicts.length > 0) {
+        return NextResponse.json(
+          { error: 'Workflows with these names already exist; pass force to import anyway', conflicts },
+          { status: 409 }
+        );
+      }
+    }
+
+    for (const { index, data } of valid) {
+      try {
+        const [workflow] = await db
+          .insert(workflows)
+          .values({
+            userId,
+            name: data.name,
+            description: data.description,
+            graphData: data.graphData || { nodes: [], edges: [] },
+            variables: data.variables,
+            timeout: data.timeout,
+          })
+          .returning({ id: workflows.id });
+        results[index].id = workflow.id;
+      } catch (error) {
+        logger.warn('Workflow import insert failed', { index, error: String(error) });
+        results[index].errors = ['Failed to save workflow'];
+      }
+    }
+
+    const imported = results.filter(r => r.id).length;
+    return NextResponse.json({ imported, failed: results.length - imported, results });
+  } catch (error) {
+    return handleApiError(error, 'POST /api/workflows/import');
+  }
+}
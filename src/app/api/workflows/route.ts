@@ -4,7 +4,8 @@ import { workflows } from '@/lib/db/schema';
 import { getAuthUserId } from '@/lib/api-auth';
 import { eq, desc } from 'drizzle-orm';
 import { rateLimit } from '@/lib/rate-limit';
-import { handleApiError } from '@/lib/api-error';
+import { apiError, handleApiError } from '@/lib/api-error';
+import { findUnknownProviders } from '@/lib/engine/validate';
 import { parseQuery, parseBody, paginationSchema, createWorkflowSchema } from '@/lib/validation';
 
 export async function GET(request: NextRequest) {
@@ -40,7 +41,12 @@ export async function POST(request: NextRequest) {
 
     const parsed = parseBody(createWorkflowSchema, body);
     if (!parsed.success) return parsed.response;
-    const { name, description, graphData, variables } = parsed.data;
+    const { name, description, graphData, variables, timeout } = parsed.data;
+
+    const providerErrors = findUnknownProviders(graphData);
+    if (providerErrors.length > 0) {
+      return apiError('Validation failed', 400, { graphData: providerErrors });
+    }
 
     const [workflow] = await db
       .insert(workflows)
@@ -50,6 +56,7 @@ export async function POST(request: NextRequest) {
         description,
         graphData: graphData || { nodes: [], edges: [] },
         variables,
+        timeout,
       })
       .returning();
 
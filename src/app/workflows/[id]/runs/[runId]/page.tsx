@@ -6,7 +6,7 @@ import { Badge } from '@/components/ui/badge';
 import { Tabs, TabsContent, TabsList, TabsTrigger } from '@/components/ui/tabs';
 import { ScrollArea } from '@/components/ui/scroll-area';
 import { Button } from '@/components/ui/button';
-import { ArrowLeft, Square, Zap, DollarSign, Loader2, CheckCircle2, XCircle, Clock, RotateCw, Download, ChevronDown, FolderOpen, FileText, Copy, Check } from 'lucide-react';
+import { ArrowLeft, Square, Pause, Play, Zap, DollarSign, Loader2, CheckCircle2, XCircle, Clock, RotateCw, Download, ChevronDown, FolderOpen, FileText, Copy, Check } from 'lucide-react';
 import Link from 'next/link';
 import { useRouter } from 'next/navigation';
 import { formatTokens, formatCost } from '@/lib/utils';
@@ -355,6 +355,34 @@ export default function RunMonitorPage({ params }: { params: Promise<{ id: strin
               <span className="font-display">{duration}</span>
             </div>
           </div>
+          {!isDone && (overallStatus === 'running' || overallStatus === 'paused') && (
+            <Button
+              variant="outline"
+              size="sm"
+              className="gap-1.5"
+              onClick={async () => {
+                const action = overallStatus === 'paused' ? 'resume' : 'pause';
+                try {
+                  const res = await fetch(`/api/runs/${runId}`, {
+                    method: 'PATCH',
+                    headers: { 'Content-Type': 'application/json' },
+                    body: JSON.stringify({ action }),
+                  });
+                  if (res.ok) {
+                    setRunData((prev) => prev ? { ...prev, status: action === 'pause' ? 'paused' : 'running' } : prev);
+                    setEvents((prev) => [...prev, {
+                      time: new Date().toLocaleTimeString(),
+                      type: action === 'pause' ? 'run-paused' : 'run-resumed',
+                      message: action === 'pause' ? 'Run paused by user' : 'Run resumed by user',
+                    }]);
+                  }
+                } catch { /* ignore */ }
+              }}
+            >
+              {overallStatus === 'paused' ? <Play className="h-3.5 w-3.5" /> : <Pause className="h-3.5 w-3.5" />}
+              {overallStatus === 'paused' ? 'Resume' : 'Pause'}
+            </Button>
+          )}
           {!isDone && (
             <Button
               variant="destructive"
@@ -396,6 +424,14 @@ export default function RunMonitorPage({ params }: { params: Promise<{ id: strin
               Download
             </Button>
           )}
+          {isDone && (
+            <Button variant="outline" size="sm" className="gap-1.5" asChild>
+              <a href={`/api/runs/${runId}/report?format=md`} target="_blank" rel="noreferrer" title="Open run report">
+                <FileText className="h-3.5 w-3.5" />
+                Report
+              </a>
+            </Button>
+          )}
           {isDone && (
             <Button
               variant="secondary"
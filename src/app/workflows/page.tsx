@@ -91,15 +91,7 @@ export default function WorkflowsPage() {
 
   const handleDuplicate = async (workflow: WorkflowItem) => {
     try {
-      const res = await fetch('/api/workflows', {
-        method: 'POST',
-        headers: { 'Content-Type': 'application/json' },
-        body: JSON.stringify({
-          name: `${workflow.name} (Copy)`,
-          description: workflow.description,
-          graphData: workflow.graphData,
-        }),
-      });
+      const res = await fetch(`/api/workflows/${workflow.id}/clone`, { method: 'POST' });
       if (res.ok) {
         const created = await res.json();
         setWorkflows((prev) => [created, ...prev]);
@@ -8,6 +8,7 @@ import { Label } from '@/components/ui/label';
 import { Textarea } from '@/components/ui/textarea';
 import { Select, SelectContent, SelectItem, SelectTrigger, SelectValue } from '@/components/ui/select';
 import { Separator } from '@/components/ui/separator';
+import { Switch } from '@/components/ui/switch';
 import { useWorkflowStore } from '@/lib/store/workflow-store';
 
 export function ConfigPanel() {
@@ -72,6 +73,7 @@ export function ConfigPanel() {
                   <SelectItem value="claude-code">Claude Code</SelectItem>
                   <SelectItem value="codex">Codex</SelectItem>
                   <SelectItem value="gemini">Gemini</SelectItem>
+                  <SelectItem value="bedrock">Claude (Bedrock)</SelectItem>
                 </SelectContent>
               </Select>
             </div>
@@ -94,6 +96,9 @@ export function ConfigPanel() {
                 placeholder="You are a helpful assistant..."
                 rows={4}
               />
+              <p className="text-xs text-dim">
+                Supports {'{{variable}}'}, {'{{workflow}}'} and {'{{date}}'}
+              </p>
             </div>
 
             {/* Max Turns */}
@@ -138,6 +143,56 @@ export function ConfigPanel() {
               />
             </div>
 
+            {(node.data.retries || 0) > 0 && (
+              <div className="flex items-center justify-between">
+                <div>
+                  <Label>Retry After Partial Output</Label>
+                  <p className="text-xs text-dim">Also retry failures after output has streamed. The output restarts from scratch.</p>
+                </div>
+                <Switch
+                  checked={!!node.data.retryAfterOutput}
+                  onCheckedChange={(checked) => updateNode(node.id, { retryAfterOutput: checked })}
+                />
+              </div>
+            )}
+
+            {/* Rate limit */}
+            <div className="space-y-2">
+              <Label>Rate Limit (requests/min)</Label>
+              <Input
+                type="number"
+                min={0}
+                value={node.data.rateLimit || 0}
+                onChange={(e) => updateNode(node.id, { rateLimit: Math.max(0, parseInt(e.target.value) || 0) })}
+              />
+              <p className="text-xs text-dim">Spaces out calls from this node, including retries. 0 = unlimited</p>
+            </div>
+
+            {/* Priority */}
+            <div className="space-y-2">
+              <Label>Priority</Label>
+              <Input
+                type="number"
+                min={-100}
+                max={100}
+                value={node.data.priority || 0}
+                onChange={(e) => updateNode(node.id, { priority: Math.min(100, Math.max(-100, parseInt(e.target.value) || 0)) })}
+              />
+              <p className="text-xs text-dim">Higher runs first when parallel nodes are queued</p>
+            </div>
+
+            {/* Response cache */}
+            <div className="flex items-center justify-between">
+              <div>
+                <Label>Cache Responses</Label>
+                <p className="text-xs text-dim">Reuse output for identical prompts. Ignored with a workspace.</p>
+              </div>
+              <Switch
+                checked={!!node.data.cacheResponses}
+                onCheckedChange={(checked) => updateNode(node.id, { cacheResponses: checked })}
+              />
+            </div>
+
             {/* Permission Mode */}
             <div className="space-y-2">
               <Label>Permission Mode</Label>
@@ -237,6 +292,33 @@ export function ConfigPanel() {
                 rows={3}
               />
             </div>
+            <div className="space-y-2">
+              <Label>Type</Label>
+              <Select
+                value={node.data.inputType || 'string'}
+                onValueChange={(value) => updateNode(node.id, { inputType: value as NonNullable<typeof node.data.inputType> })}
+              >
+                <SelectTrigger>
+                  <SelectValue placeholder="Select type" />
+                </SelectTrigger>
+                <SelectContent>
+                  <SelectItem value="string">String</SelectItem>
+                  <SelectItem value="number">Number</SelectItem>
+                  <SelectItem value="boolean">Boolean</SelectItem>
+                  <SelectItem value="json">JSON</SelectItem>
+                </SelectContent>
+              </Select>
+            </div>
+            <div className="flex items-center justify-between">
+              <div>
+                <Label>Required</Label>
+                <p className="text-xs text-dim">Fail the run if no value or default is provided</p>
+              </div>
+              <Switch
+                checked={!!node.data.required}
+                onCheckedChange={(checked) => updateNode(node.id, { required: checked })}
+              />
+            </div>
           </>
         )}
 
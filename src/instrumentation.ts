@@ -0,0 +1,26 @@
+export async function register() {
+  if (process.env.NEXT_RUNTIME !== 'nodejs') return;
+
+  const { shutdownWorkflowRuns, dispatchQueuedRuns, reconcilePausedRuns } = await import('@/lib/engine/run-simple');
+  const { getConfig } = await import('@/lib/config');
+  const { logger } = await import('@/lib/logger');
+
+  // Close out runs paused before a restart and pick up runs still queued
+  void reconcilePausedRuns();
+  void dispatchQueuedRuns();
+
+  let shuttingDown = false;
+  const shutdown = async (signal: string) => {
+    if (shuttingDown) return;
+    shuttingDown = true;
+    logger.info('Shutdown signal received, draining active runs', { signal });
+    try {
+      await shutdownWorkflowRuns(getConfig().app.shutdownTimeoutMs);
+    } finally {
+      process.exit(0);
+    }
+  };
+
+  process.once('SIGTERM', () => void shutdown('SIGTERM'));
+  process.once('SIGINT', () => void shutdown('SIGINT'));
+}
@@ -0,0 +1,113 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import {
+  claimRunSlot,
+  drainActiveRuns,
+  getActiveRun,
+  isDraining,
+  registerActiveRun,
+  resetActiveRuns,
+  RunCapacityError,
+} from '../engine/active-runs';
+import { Executor } from '../engine/executor';
+import type { ProviderOptions } from '@/types/provider';
+
+// Provider that runs until aborted, standing in for a long CLI call
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'slow',
+    name: 'Slow',
+    async *stream(_messages: unknown, options: ProviderOptions) {
+      await new Promise<void>(resolve => options.signal?.addEventListener('abort', () => resolve(), { once: true }));
+      yield { type: 'done' as const, content: '' };
+    },
+  }),
+}));
+
+function fakeRun(stopsOnAbort: boolean) {
+  let finish: () => void = () => {};
+  const done = new Promise<void>(resolve => { finish = resolve; });
+  const executor = {
+    abort: vi.fn(() => { if (stopsOnAbort) finish(); }),
+  } as unknown as Executor;
+  return { executor, done, finish };
+}
+
+describe('active runs', () => {
+  beforeEach(() => {
+    resetActiveRuns();
+  });
+
+  it('drops runs from the registry once they finish', async () => {
+    const run = fakeRun(false);
+    registerActiveRun('run-1', run.executor, run.done);
+    expect(getActiveRun('run-1')).toBeDefined();
+
+    run.finish();
+    await run.done;
+    await Promise.resolve();
+    expect(getActiveRun('run-1')).toBeUndefined();
+  });
+
+  it('aborts every run and returns those still in flight at the deadline', async () => {
+    const stopping = fakeRun(true);
+    const stuck = fakeRun(false);
+    registerActiveRun('stopping', stopping.executor, stopping.done);
+    registerActiveRun('stuck', stuck.executor, stuck.done);
+
+    const interrupted = await drainActiveRuns(20);
+
+    expect(isDraining()).toBe(true);
+    expect(stopping.executor.abort).toHaveBeenCalled();
+    expect(stuck.executor.abort).toHaveBeenCalled();
+    expect(interrupted.map(r => r.runId)).toEqual(['stuck']);
+  });
+
+  it('stops a long-running node well before the deadline', async () => {
+    const executor = new Executor(
+      [{ id: 'slow', type: 'agent', position: { x: 0, y: 0 }, data: { label: 'slow' } }],
+      [],
+      {}
+    );
+    const done = executor.execute();
+    registerActiveRun('long', executor, done);
+    await new Promise(resolve => setTimeout(resolve, 20));
+
+    const started = Date.now();
+    const interrupted = await drainActiveRuns(5000);
+
+    expect(interrupted).toEqual([]);
+    expect(Date.now() - started).toBeLessThan(1000);
+    expect((await done).status).toBe('cancelled');
+  });
+
+  it('resolves immediately when nothing is running', async () => {
+    expect(await drainActiveRuns(10_000)).toEqual([]);
+    expect(isDraining()).toBe(true);
+  });
+
+  it('rejects new runs at the concurrency limit', () => {
+    const run = fakeRun(false);
+    registerActiveRun('run-1', run.executor, run.done);
+    const release = claimRunSlot(2);
+
+    expect(() => claimRunSlot(2)).toThrow(RunCapacityError);
+
+    release();
+    expect(() => claimRunSlot(2)()).not.toThrow();
+  });
+
+  it('frees a slot when an active run finishes', async () => {
+    const run = fakeRun(false);
+    registerActiveRun('run-1', run.executor, run.done);
+    expect(() => claimRunSlot(1)).toThrow('Too many concurrent runs (limit 1)');
+
+    run.finish();
+    await new Promise(resolve => setTimeout(resolve, 0));
+
+    expect(() => claimRunSlot(1)).not.toThrow();
+  });
+
+  it('treats a limit of 0 as unlimited', () => {
+    for (let i = 0; i < 50; i++) claimRunSlot(0);
+  });
+});
@@ -0,0 +1,61 @@
+import { describe, it, expect, vi, afterEach } from 'vitest';
+import { mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { collectArtifacts } from '../engine/artifacts';
+import { Executor } from '../engine/executor';
+import type { NodeRunState, WorkflowNode } from '../engine/types';
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'writer',
+    name: 'Writer',
+    async *stream() {
+      yield { type: 'text' as const, content: '# Report' };
+    },
+  }),
+}));
+
+describe('collectArtifacts', () => {
+  it('lists files from every node, last writer winning', () => {
+    const states: Record<string, NodeRunState> = {
+      b: { status: 'completed', completedAt: new Date(2000), files: [{ path: 'out/report.md', size: 20 }] },
+      a: {
+        status: 'completed',
+        completedAt: new Date(1000),
+        files: [{ path: 'out/report.md', size: 10 }, { path: 'data.json', size: 5 }],
+      },
+      c: { status: 'skipped' },
+    };
+
+    expect(collectArtifacts(states)).toEqual([
+      { nodeId: 'a', path: 'data.json', size: 5 },
+      { nodeId: 'b', path: 'out/report.md', size: 20 },
+    ]);
+  });
+});
+
+describe('Executor artifacts path', () => {
+  let workspace: string;
+
+  afterEach(() => {
+    rmSync(workspace, { recursive: true, force: true });
+  });
+
+  it('saves agent output under the run artifacts directory and records it', async () => {
+    workspace = mkdtempSync(join(tmpdir(), 'cadre-artifacts-'));
+    const node: WorkflowNode = {
+      id: 'a', type: 'agent', position: { x: 0, y: 0 }, data: { label: 'Write report', workspace: 'safe' },
+    };
+
+    const result = await new Executor([node], [], {
+      workspacePath: workspace,
+      artifactsPath: join(workspace, 'artifacts', 'run-1'),
+    }).execute();
+
+    expect(readFileSync(join(workspace, 'artifacts', 'run-1', 'Write_report.md'), 'utf-8')).toBe('# Report');
+    expect(collectArtifacts(result.nodeStates)).toEqual([
+      { nodeId: 'a', path: join('artifacts', 'run-1', 'Write_report.md'), size: 8 },
+    ]);
+  });
+});
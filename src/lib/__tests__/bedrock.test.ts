@@ -0,0 +1,32 @@
+import { describe, it, expect } from 'vitest';
+import { BedrockProvider } from '../providers/bedrock';
+
+class InspectableBedrock extends BedrockProvider {
+  env() {
+    return this.buildEnv();
+  }
+}
+
+describe('BedrockProvider', () => {
+  it('points the Claude CLI at Bedrock in the configured region', () => {
+    const env = new InspectableBedrock('eu-west-1').env();
+
+    expect(env.CLAUDE_CODE_USE_BEDROCK).toBe('1');
+    expect(env.AWS_REGION).toBe('eu-west-1');
+  });
+
+  it('keeps the rest of the environment for the AWS credential chain', () => {
+    process.env.AWS_PROFILE = 'cadre-test';
+    try {
+      expect(new InspectableBedrock('us-east-1').env().AWS_PROFILE).toBe('cadre-test');
+    } finally {
+      delete process.env.AWS_PROFILE;
+    }
+  });
+
+  it('registers under its own id', () => {
+    const provider = new BedrockProvider('us-east-1');
+    expect(provider.id).toBe('bedrock');
+    expect(provider.name).toBe('Claude (Bedrock)');
+  });
+});
@@ -98,4 +98,53 @@ describe('config', () => {
     const { getConfig } = await getConfigModule();
     expect(getConfig().db.poolSize).toBe(10);
   });
+
+  it('SHUTDOWN_TIMEOUT_MS defaults to 10s', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.SHUTDOWN_TIMEOUT_MS;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().app.shutdownTimeoutMs).toBe(10000);
+  });
+
+  it('MAX_UPSTREAM_BYTES defaults to 100KB', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.MAX_UPSTREAM_BYTES;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().engine.maxUpstreamBytes).toBe(100000);
+  });
+
+  it('NODE_CONCURRENCY defaults to 4', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.NODE_CONCURRENCY;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().engine.concurrency).toBe(4);
+  });
+
+  it('MAX_CONCURRENT_RUNS defaults to 10', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.MAX_CONCURRENT_RUNS;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().engine.maxConcurrentRuns).toBe(10);
+  });
+
+  it('PROVIDER_RATE_LIMIT defaults to unlimited', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.PROVIDER_RATE_LIMIT;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().engine.providerRateLimit).toBe(0);
+  });
+
+  it('RESPONSE_CACHE_TTL_MS defaults to one hour', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.RESPONSE_CACHE_TTL_MS;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().engine.responseCacheTtlMs).toBe(3600000);
+  });
+
+  it('RUN_TIMEOUT_SECONDS defaults to disabled', async () => {
+    process.env.CADRE_ENV = 'local';
+    delete process.env.RUN_TIMEOUT_SECONDS;
+    const { getConfig } = await getConfigModule();
+    expect(getConfig().engine.runTimeoutSeconds).toBe(0);
+  });
 });
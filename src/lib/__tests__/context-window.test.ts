@@ -0,0 +1,188 @@
+import { describe, it, expect, vi } from 'vitest';
+import { contextWindowFor, countPromptTokens, estimateTokens, fitToContextWindow, promptTokenCounter } from '../engine/context-window';
+import { Executor } from '../engine/executor';
+import type { WorkflowNode } from '../engine/types';
+import type { ProviderMessage } from '@/types/provider';
+
+const prompts = vi.hoisted(() => [] as string[]);
+const tokenCounter = vi.hoisted(() => ({ count: undefined as ((text: string) => number) | undefined }));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'mock',
+    name: 'Mock',
+    countTokens: tokenCounter.count && (async (messages: ProviderMessage[]) => tokenCounter.count!(messages[0].content)),
+    async *stream(messages: ProviderMessage[]) {
+      prompts.push(messages[messages.length - 1].content);
+      yield { type: 'done' as const, content: '' };
+    },
+  }),
+}));
+
+const section = (label: string, tokens: number) => ({ label, text: 'x'.repeat(tokens * 4) });
+
+describe('estimateTokens', () => {
+  it('approximates four characters per token', () => {
+    expect(estimateTokens('')).toBe(0);
+    expect(estimateTokens('abcd')).toBe(1);
+    expect(estimateTokens('abcde')).toBe(2);
+  });
+});
+
+describe('countPromptTokens', () => {
+  const messages: ProviderMessage[] = [
+    { role: 'system', content: 'abcd' },
+    { role: 'user', content: 'abcdefgh' },
+  ];
+  const base = { id: 'p', name: 'P', stream: async function* () {}, validateCli: async () => true };
+
+  it('uses the provider counter when available', async () => {
+    const countTokens = vi.fn(async () => 42);
+    expect(await countPromptTokens({ ...base, countTokens }, messages)).toBe(42);
+    expect(countTokens).toHaveBeenCalledWith(messages, {});
+  });
+
+  it('falls back to the estimate without a counter or when it fails', async () => {
+    expect(await countPromptTokens(base, messages)).toBe(3);
+    const countTokens = async () => { throw new Error('unavailable'); };
+    expect(await countPromptTokens({ ...base, countTokens }, messages)).toBe(3);
+  });
+});
+
+describe('contextWindowFor', () => {
+  it('prefers the override, then the provider table', () => {
+    expect(contextWindowFor('gemini', 1000)).toBe(1000);
+    expect(contextWindowFor('gemini')).toBe(1_000_000);
+    expect(contextWindowFor('unknown')).toBe(200_000);
+  });
+});
+
+describe('fitToContextWindow', () => {
+  it('keeps everything that fits', () => {
+    const result = fitToContextWindow('sys', [section('a', 10), section('b', 10)], 100);
+    expect(result.dropped).toEqual([]);
+    expect(result.sections).toHaveLength(2);
+  });
+
+  it('drops the oldest sections first', () => {
+    // budget is 80 tokens after the 20% reserve
+    const result = fitToContextWindow('', [section('a', 40), section('b', 30), section('c', 30)], 100);
+    expect(result.dropped).toEqual(['a']);
+    expect(result.sections.map(s => s.label)).toEqual(['b', 'c']);
+  });
+
+  it('fails when the newest section alone is too large', () => {
+    expect(() => fitToContextWindow('', [section('a', 10), section('big', 90)], 100))
+      .toThrow('Prompt exceeds the context window: input from "big" is ~90 tokens, limit is 80');
+  });
+
+  it('uses the given token counter', () => {
+    const count = (text: string) => (text === 'heavy' ? 90 : 1);
+    const result = fitToContextWindow('', [{ label: 'a', text: 'heavy' }, { label: 'b', text: 'light' }], 100, count);
+    expect(result.dropped).toEqual(['a']);
+  });
+});
+
+describe('promptTokenCounter', () => {
+  it('counts each distinct text once with the provider', async () => {
+    const countTokens = vi.fn(async (messages: ProviderMessage[]) => messages[0].content.length * 10);
+    const provider = { id: 'p', name: 'P', stream: async function* () {}, validateCli: async () => true, countTokens };
+
+    const count = await promptTokenCounter(provider, ['ab', 'ab', 'abc']);
+
+    expect(count('ab')).toBe(20);
+    expect(count('abc')).toBe(30);
+    expect(countTokens).toHaveBeenCalledTimes(2);
+  });
+});
+
+describe('Executor context window guard', () => {
+  it('drops older upstream outputs for a tiny window', async () => {
+    const node = (id: string, data: Partial<WorkflowNode['data']>): WorkflowNode => ({
+      id,
+      type: id === 'agent' ? 'agent' : 'transform',
+      position: { x: 0, y: 0 },
+      data: { label: id, ...data },
+    });
+    const executor = new Executor(
+      [
+        node('old', { template: 'o'.repeat(200) }),
+        node('new', { template: 'n'.repeat(40) }),
+        node('agent', { contextWindow: 50 }),
+      ],
+      [
+        { id: 'e1', source: 'old', target: 'agent' },
+        { id: 'e2', source: 'new', target: 'agent' },
+      ],
+      {}
+    );
+
+    const result = await executor.execute();
+
+    expect(result.nodeStates.agent.status).toBe('completed');
+    expect(prompts).toHaveLength(1);
+    expect(prompts[0]).toContain('[Output from "new"]');
+    expect(prompts[0]).not.toContain('[Output from "old"]');
+  });
+
+  it('fails a prompt that can never fit without retrying', async () => {
+    prompts.length = 0;
+    const events: string[] = [];
+    const node = (id: string, data: Partial<WorkflowNode['data']>): WorkflowNode => ({
+      id,
+      type: id === 'agent' ? 'agent' : 'transform',
+      position: { x: 0, y: 0 },
+      data: { label: id, ...data },
+    });
+    const executor = new Executor(
+      [
+        node('big', { template: 'b'.repeat(400) }),
+        node('agent', { contextWindow: 50, retries: 3 }),
+      ],
+      [{ id: 'e1', source: 'big', target: 'agent' }],
+      { onEvent: (event) => { events.push(event.type); } }
+    );
+
+    const result = await executor.execute();
+
+    expect(result.nodeStates.agent.status).toBe('failed');
+    expect(result.nodeStates.agent.error).toContain('Prompt exceeds the context window');
+    expect(result.nodeStates.agent.attempts).toBeUndefined();
+    expect(events).not.toContain('node-retry');
+    expect(prompts).toHaveLength(0);
+  });
+
+  it('fits the prompt with the provider token counter', async () => {
+    prompts.length = 0;
+    // Short text that the provider counts as far over the window
+    tokenCounter.count = (text) => (text.includes('heavy') ? 1000 : 1);
+    const node = (id: string, data: Partial<WorkflowNode['data']>): WorkflowNode => ({
+      id,
+      type: id === 'agent' ? 'agent' : 'transform',
+      position: { x: 0, y: 0 },
+      data: { label: id, ...data },
+    });
+    const executor = new Executor(
+      [
+        node('old', { template: 'heavy' }),
+        node('new', { template: 'light' }),
+        node('agent', { contextWindow: 500 }),
+      ],
+      [
+        { id: 'e1', source: 'old', target: 'agent' },
+        { id: 'e2', source: 'new', target: 'agent' },
+      ],
+      {}
+    );
+
+    try {
+      await executor.execute();
+    } finally {
+      tokenCounter.count = undefined;
+    }
+
+    expect(prompts).toHaveLength(1);
+    expect(prompts[0]).toContain('[Output from "new"]');
+    expect(prompts[0]).not.toContain('[Output from "old"]');
+  });
+});
@@ -0,0 +1,64 @@
+import { describe, it, expect, vi } from 'vitest';
+import { Executor } from '../engine/executor';
+import type { WorkflowEdge, WorkflowNode } from '../engine/types';
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'mock',
+    name: 'Mock',
+    async *stream() {
+      yield {
+        type: 'text' as const,
+        content: 'Planned.\n```output-manifest\n{"meta": {"owner": "ana", "tags": ["api"]}, "steps": [{"id": 1}]}\n```',
+      };
+      yield { type: 'done' as const, content: '', tokens: { input: 1, output: 1 } };
+    },
+  }),
+}));
+
+const nodes: WorkflowNode[] = [
+  {
+    id: 'plan',
+    type: 'agent',
+    position: { x: 0, y: 0 },
+    data: {
+      label: 'Plan',
+      outputSchema: {
+        fields: [
+          { name: 'meta', type: 'object', required: true },
+          { name: 'steps', type: 'object[]' },
+        ],
+      },
+    },
+  },
+  {
+    id: 'use',
+    type: 'transform',
+    position: { x: 0, y: 100 },
+    data: { label: 'Use', template: 'owner={{node_plan_data.meta.owner}} meta={{node_plan_data.meta}}' },
+  },
+];
+
+const edges: WorkflowEdge[] = [{ id: 'e1', source: 'plan', target: 'use' }];
+
+describe('Executor structured output', () => {
+  it('stores object outputs structurally for downstream nodes', async () => {
+    const result = await new Executor(nodes, edges, {}).execute();
+
+    expect(result.status).toBe('completed');
+    expect(result.context.node_plan_data).toEqual({
+      meta: { owner: 'ana', tags: ['api'] },
+      steps: [{ id: 1 }],
+    });
+    expect(result.nodeStates.use.output).toBe('owner=ana meta={"owner":"ana","tags":["api"]}');
+  });
+
+  it('keeps fields parsed while streaming on the node state', async () => {
+    const result = await new Executor(nodes, edges, {}).execute();
+
+    expect(result.nodeStates.plan.partialFields).toEqual({
+      meta: { owner: 'ana', tags: ['api'] },
+      steps: [{ id: 1 }],
+    });
+  });
+});
@@ -0,0 +1,53 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { Executor } from '../engine/executor';
+import type { WorkflowNode } from '../engine/types';
+import type { ProviderMessage, ProviderOptions } from '@/types/provider';
+
+const seen = vi.hoisted(() => ({
+  providers: [] as string[],
+  maxTurns: [] as (number | undefined)[],
+  models: [] as (string | undefined)[],
+}));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: (id: string) => ({
+    id,
+    name: id,
+    async *stream(_messages: ProviderMessage[], options: ProviderOptions) {
+      seen.providers.push(id);
+      seen.maxTurns.push(options.maxTurns);
+      seen.models.push(options.model);
+      yield { type: 'text' as const, content: 'ok' };
+    },
+  }),
+}));
+
+function agent(id: string, data: Partial<WorkflowNode['data']> = {}): WorkflowNode {
+  return { id, type: 'agent', position: { x: 0, y: 0 }, data: { label: id, ...data } };
+}
+
+describe('Executor run overrides', () => {
+  beforeEach(() => {
+    seen.providers = [];
+    seen.maxTurns = [];
+    seen.models = [];
+  });
+
+  it('applies overrides to every agent node', async () => {
+    const nodes = [agent('a', { provider: 'codex', maxTurns: 3 }), agent('b', { provider: 'gemini' })];
+
+    await new Executor(nodes, [], { overrides: { provider: 'claude-code', model: 'opus', maxTurns: 7 } }).execute();
+
+    expect(seen.providers).toEqual(['claude-code', 'claude-code']);
+    expect(seen.models).toEqual(['opus', 'opus']);
+    expect(seen.maxTurns).toEqual([7, 7]);
+  });
+
+  it('falls back to node settings without overrides', async () => {
+    await new Executor([agent('a', { provider: 'codex', maxTurns: 3 })], [], {}).execute();
+
+    expect(seen.providers).toEqual(['codex']);
+    expect(seen.models).toEqual([undefined]);
+    expect(seen.maxTurns).toEqual([3]);
+  });
+});
@@ -0,0 +1,78 @@
+import { describe, it, expect } from 'vitest';
+import { Executor } from '../engine/executor';
+import type { ExecutionEvent, WorkflowEdge, WorkflowNode } from '../engine/types';
+
+function node(id: string, data: Partial<WorkflowNode['data']> = {}): WorkflowNode {
+  return { id, type: 'transform', position: { x: 0, y: 0 }, data: { label: id, template: id, ...data } };
+}
+
+function edge(source: string, target: string): WorkflowEdge {
+  return { id: `${source}-${target}`, source, target };
+}
+
+describe('Executor pause/resume', () => {
+  it('holds the next node until resumed', async () => {
+    const events: ExecutionEvent[] = [];
+    const executor: Executor = new Executor([node('a'), node('b')], [edge('a', 'b')], {
+      onEvent: (event) => {
+        events.push(event);
+        if (event.type === 'node-complete' && event.nodeId === 'a') executor.pause();
+      },
+    });
+
+    const done = executor.execute();
+    await new Promise(resolve => setTimeout(resolve, 20));
+
+    expect(executor.isPaused()).toBe(true);
+    expect(events.some(e => e.type === 'node-start' && e.nodeId === 'b')).toBe(false);
+
+    executor.resume();
+    const result = await done;
+
+    expect(result.status).toBe('completed');
+    expect(result.nodeStates.b.status).toBe('completed');
+    expect(events.map(e => e.type)).toContain('run-paused');
+    expect(events.map(e => e.type)).toContain('run-resumed');
+  });
+
+  it('abort releases a paused run', async () => {
+    const started: string[] = [];
+    const executor: Executor = new Executor([node('a'), node('b')], [edge('a', 'b')], {
+      onEvent: (event) => {
+        if (event.type === 'node-start' && event.nodeId) started.push(event.nodeId);
+        if (event.type === 'node-complete' && event.nodeId === 'a') executor.pause();
+      },
+    });
+
+    const done = executor.execute();
+    await new Promise(resolve => setTimeout(resolve, 20));
+    executor.abort();
+    const result = await done;
+
+    expect(result.status).toBe('cancelled');
+    expect(started).toEqual(['a']);
+  });
+});
+
+describe('Executor node cancel', () => {
+  it('fails the cancelled node while its siblings finish', async () => {
+    const gate: WorkflowNode = { id: 'stuck', type: 'gate', position: { x: 0, y: 0 }, data: { label: 'stuck' } };
+    const executor: Executor = new Executor(
+      [gate, node('sibling'), node('next'), node('after-stuck')],
+      [edge('sibling', 'next'), edge('stuck', 'after-stuck')],
+      {
+        onEvent: (event) => {
+          if (event.type === 'node-waiting') expect(executor.cancelNode('stuck')).toBe(true);
+        },
+      }
+    );
+
+    const result = await executor.execute();
+
+    expect(result.nodeStates.stuck).toMatchObject({ status: 'failed', error: 'Node "stuck" was cancelled' });
+    expect(result.nodeStates.sibling.status).toBe('completed');
+    expect(result.nodeStates.next.status).toBe('completed');
+    expect(result.nodeStates['after-stuck'].status).toBe('skipped');
+    expect(executor.cancelNode('sibling')).toBe(false);
+  });
+});
@@ -0,0 +1,46 @@
+import { describe, it, expect } from 'vitest';
+import { Executor } from '../engine/executor';
+import type { WorkflowEdge, WorkflowNode } from '../engine/types';
+
+function node(id: string, type: WorkflowNode['type'], data: Partial<WorkflowNode['data']> = {}): WorkflowNode {
+  return { id, type, position: { x: 0, y: 0 }, data: { label: id, ...data } };
+}
+
+function edge(source: string, target: string, sourceHandle?: string): WorkflowEdge {
+  return { id: `${source}-${target}`, source, target, sourceHandle };
+}
+
+function triage(kind: string) {
+  return new Executor(
+    [
+      node('route', 'router', {
+        routes: [
+          { label: 'bug', condition: "kind === 'bug'" },
+          { label: 'feature', condition: "kind === 'feature'" },
+        ],
+      }),
+      node('fix', 'transform', { template: 'fixing' }),
+      node('plan', 'transform', { template: 'planning' }),
+    ],
+    [edge('route', 'fix', 'bug'), edge('route', 'plan', 'feature')],
+    { variables: { kind } }
+  );
+}
+
+describe('Executor router node', () => {
+  it('runs only the branch whose condition matches', async () => {
+    const result = await triage('bug').execute();
+
+    expect(result.status).toBe('completed');
+    expect(result.nodeStates.route.output).toBe('bug');
+    expect(result.nodeStates.fix.status).toBe('completed');
+    expect(result.nodeStates.plan.status).toBe('skipped');
+  });
+
+  it('switches branches with the input', async () => {
+    const result = await triage('feature').execute();
+
+    expect(result.nodeStates.fix.status).toBe('skipped');
+    expect(result.nodeStates.plan.status).toBe('completed');
+  });
+});
@@ -0,0 +1,121 @@
+import { describe, it, expect, vi } from 'vitest';
+import { Executor } from '../engine/executor';
+import type { WorkflowEdge, WorkflowNode } from '../engine/types';
+import type { ProviderMessage, ProviderOptions } from '@/types/provider';
+
+// Signals handed to the provider, to check that aborts reach it
+const signals: AbortSignal[] = [];
+
+// Provider that never produces output and only returns once aborted
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'slow',
+    name: 'Slow',
+    async *stream(_messages: ProviderMessage[], options: ProviderOptions) {
+      if (options.signal) signals.push(options.signal);
+      await new Promise<void>((resolve) => {
+        if (options.signal?.aborted) return resolve();
+        options.signal?.addEventListener('abort', () => resolve(), { once: true });
+      });
+      yield { type: 'done' as const, content: '' };
+    },
+  }),
+}));
+
+function node(id: string, type: WorkflowNode['type'], data: Partial<WorkflowNode['data']> = {}): WorkflowNode {
+  return { id, type, position: { x: 0, y: 0 }, data: { label: id, ...data } };
+}
+
+function edge(source: string, target: string): WorkflowEdge {
+  return { id: `${source}-${target}`, source, target };
+}
+
+describe('Executor node timeout', () => {
+  it('fails only the node that exceeds its timeout', async () => {
+    const executor = new Executor(
+      [
+        node('in', 'input'),
+        node('slow', 'agent', { timeout: 0.05 }),
+        node('fast', 'transform', { template: 'ok' }),
+      ],
+      [edge('in', 'slow'), edge('in', 'fast')],
+      {}
+    );
+
+    const result = await executor.execute();
+
+    expect(result.status).toBe('failed');
+    expect(result.nodeStates.slow.status).toBe('failed');
+    expect(result.nodeStates.slow.error).toBe('Node "slow" timed out after 0.05s');
+    expect(result.nodeStates.fast.status).toBe('completed');
+  });
+
+  it('skips downstream nodes of a timed-out node', async () => {
+    const executor = new Executor(
+      [node('slow', 'agent', { timeout: 0.05 }), node('after', 'transform', { template: 'x' })],
+      [edge('slow', 'after')],
+      {}
+    );
+
+    const result = await executor.execute();
+
+    expect(result.nodeStates.after.status).toBe('skipped');
+  });
+
+  it('times out a gate waiting for approval', async () => {
+    const executor = new Executor([node('gate', 'gate', { timeout: 0.05 })], [], {});
+
+    const result = await executor.execute();
+
+    expect(result.nodeStates.gate.status).toBe('failed');
+    expect(result.nodeStates.gate.error).toBe('Node "gate" timed out after 0.05s');
+  });
+});
+
+describe('Executor run timeout', () => {
+  it('fails the run and aborts in-flight nodes at the deadline', async () => {
+    const executor = new Executor(
+      [node('slow', 'agent'), node('after', 'transform', { template: 'x' })],
+      [edge('slow', 'after')],
+      { timeout: 0.05 }
+    );
+
+    const result = await executor.execute();
+
+    expect(result.status).toBe('failed');
+    expect(result.context.error).toBe('Run timed out after 0.05s');
+    expect(result.nodeStates.slow.status).toBe('failed');
+    expect(result.nodeStates.slow.error).toBe('Run timed out after 0.05s');
+    expect(result.nodeStates.after).toBeUndefined();
+  });
+
+  it('does not fire when the run finishes in time', async () => {
+    const executor = new Executor([node('fast', 'transform', { template: 'ok' })], [], { timeout: 1 });
+
+    const result = await executor.execute();
+
+    expect(result.status).toBe('completed');
+    expect(result.context.error).toBeUndefined();
+  });
+});
+
+describe('Executor cancel', () => {
+  it('aborts the provider of an in-flight node', async () => {
+    signals.length = 0;
+    const executor: Executor = new Executor([node('slow', 'agent', { retries: 2 })], [], {
+      onEvent: (event) => {
+        if (event.type === 'node-start') setTimeout(() => executor.abort(), 20);
+      },
+    });
+
+    const started = Date.now();
+    const result = await executor.execute();
+
+    expect(result.status).toBe('cancelled');
+    expect(result.nodeStates.slow.error).toBe('Run was cancelled');
+    expect(result.nodeStates.slow.attempts).toBeUndefined();
+    expect(signals).toHaveLength(1);
+    expect(signals[0].aborted).toBe(true);
+    expect(Date.now() - started).toBeLessThan(1000);
+  });
+});
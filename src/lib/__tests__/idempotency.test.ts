@@ -0,0 +1,59 @@
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest';
+import { withIdempotency } from '../idempotency';
+
+describe('withIdempotency', () => {
+  beforeEach(() => {
+    vi.useFakeTimers();
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+  });
+
+  it('runs once for repeated keys and replays the result', async () => {
+    const start = vi.fn().mockResolvedValue({ runId: 'run-1' });
+
+    const first = await withIdempotency('test-replay', start);
+    const second = await withIdempotency('test-replay', start);
+
+    expect(start).toHaveBeenCalledTimes(1);
+    expect(first).toEqual({ value: { runId: 'run-1' }, replayed: false });
+    expect(second).toEqual({ value: { runId: 'run-1' }, replayed: true });
+  });
+
+  it('shares an in-flight call between concurrent requests', async () => {
+    const start = vi.fn().mockResolvedValue('run-2');
+
+    const [a, b] = await Promise.all([
+      withIdempotency('test-concurrent', start),
+      withIdempotency('test-concurrent', start),
+    ]);
+
+    expect(start).toHaveBeenCalledTimes(1);
+    expect(a.value).toBe('run-2');
+    expect(b.value).toBe('run-2');
+  });
+
+  it('forgets failed calls so they can be retried', async () => {
+    const start = vi.fn()
+      .mockRejectedValueOnce(new Error('boom'))
+      .mockResolvedValue('run-3');
+
+    await expect(withIdempotency('test-retry', start)).rejects.toThrow('boom');
+    const retry = await withIdempotency('test-retry', start);
+
+    expect(start).toHaveBeenCalledTimes(2);
+    expect(retry).toEqual({ value: 'run-3', replayed: false });
+  });
+
+  it('starts a new call once the key expires', async () => {
+    const start = vi.fn().mockResolvedValue('run-4');
+
+    await withIdempotency('test-expiry', start);
+    vi.advanceTimersByTime(10 * 60_000 + 1);
+    const later = await withIdempotency('test-expiry', start);
+
+    expect(start).toHaveBeenCalledTimes(2);
+    expect(later.replayed).toBe(false);
+  });
+});
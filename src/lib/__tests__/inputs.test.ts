@@ -0,0 +1,86 @@
+import { describe, it, expect } from 'vitest';
+import { InputValidationError, requireValidInputs, resolveInputs } from '../engine/inputs';
+import { Executor } from '../engine/executor';
+import type { WorkflowNode } from '../engine/types';
+
+function input(id: string, data: Partial<WorkflowNode['data']> = {}): WorkflowNode {
+  return { id, type: 'input', position: { x: 0, y: 0 }, data: { label: id, ...data } };
+}
+
+describe('resolveInputs', () => {
+  it('reports a missing required input', () => {
+    const result = resolveInputs([input('q', { variableName: 'query', required: true })], {});
+    expect(result.errors).toEqual(['Missing required input "query"']);
+  });
+
+  it('applies the default when no value is supplied', () => {
+    const result = resolveInputs([input('q', { variableName: 'query', required: true, defaultValue: 'hello' })], {});
+    expect(result.errors).toEqual([]);
+    expect(result.values).toEqual({ query: 'hello' });
+  });
+
+  it('prefers a supplied value over the default', () => {
+    const result = resolveInputs([input('q', { defaultValue: 'hello' })], { input: 'world' });
+    expect(result.values).toEqual({ input: 'world' });
+  });
+
+  it('type-checks supplied values', () => {
+    const result = resolveInputs(
+      [
+        input('n', { variableName: 'count', inputType: 'number' }),
+        input('b', { variableName: 'flag', inputType: 'boolean' }),
+        input('j', { variableName: 'config', inputType: 'json' }),
+      ],
+      { count: 'many', flag: 'yes', config: '{' }
+    );
+    expect(result.errors).toEqual([
+      'Input "count" must be a number',
+      'Input "flag" must be a boolean',
+      'Input "config" must be valid JSON',
+    ]);
+  });
+
+  it('ignores optional inputs without a value', () => {
+    expect(resolveInputs([input('q')], {})).toEqual({ values: {}, errors: [] });
+  });
+});
+
+describe('requireValidInputs', () => {
+  it('throws with every problem listed', () => {
+    const nodes = [
+      input('q', { variableName: 'query', required: true }),
+      input('n', { variableName: 'count', inputType: 'number' }),
+    ];
+    const error = (() => {
+      try {
+        requireValidInputs(nodes, { count: 'many' });
+      } catch (err) {
+        return err;
+      }
+    })();
+
+    expect(error).toBeInstanceOf(InputValidationError);
+    expect((error as InputValidationError).errors).toEqual([
+      'Missing required input "query"',
+      'Input "count" must be a number',
+    ]);
+  });
+
+  it('returns the resolved values when valid', () => {
+    expect(requireValidInputs([input('q', { variableName: 'query', required: true })], { query: 'hi' })).toEqual({ query: 'hi' });
+  });
+});
+
+describe('Executor inputs', () => {
+  it('fails fast when a required input is missing', async () => {
+    const executor = new Executor([input('q', { variableName: 'query', required: true })], [], {});
+    await expect(executor.execute()).rejects.toThrow('Invalid inputs: Missing required input "query"');
+  });
+
+  it('feeds the default value through the input node', async () => {
+    const executor = new Executor([input('q', { variableName: 'query', defaultValue: 'hello' })], [], {});
+    const result = await executor.execute();
+    expect(result.nodeStates.q.output).toBe('hello');
+    expect(result.context.query).toBe('hello');
+  });
+});
@@ -104,4 +104,26 @@ describe('logger', () => {
     logger.debug('should appear');
     expect(console.debug).toHaveBeenCalledTimes(1);
   });
+
+  it('child loggers add their fields to every line', async () => {
+    process.env.LOG_LEVEL = 'info';
+    process.env.CADRE_ENV = 'prod';
+    const logger = await getLogger();
+    const nodeLog = logger.child({ runId: 'run-1' }).child({ nodeId: 'a' });
+    nodeLog.info('working', { attempt: 2 });
+    const output = JSON.parse((console.info as ReturnType<typeof vi.fn>).mock.calls[0][0]);
+    expect(output.runId).toBe('run-1');
+    expect(output.nodeId).toBe('a');
+    expect(output.attempt).toBe(2);
+  });
+
+  it('child fields do not leak into the parent logger', async () => {
+    process.env.LOG_LEVEL = 'info';
+    process.env.CADRE_ENV = 'prod';
+    const logger = await getLogger();
+    logger.child({ runId: 'run-1' });
+    logger.info('plain');
+    const output = JSON.parse((console.info as ReturnType<typeof vi.fn>).mock.calls[0][0]);
+    expect(output.runId).toBeUndefined();
+  });
 });
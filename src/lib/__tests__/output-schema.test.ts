@@ -0,0 +1,205 @@
+import { describe, it, expect } from 'vitest';
+import {
+  PartialOutputTracker,
+  buildOutputSchemaInstructions,
+  extractLastJsonBlock,
+  parseOpenManifest,
+  repairJson,
+  validateManifest,
+  validateOutput,
+} from '../engine/output-schema';
+import type { OutputSchema } from '../engine/types';
+
+const schema: OutputSchema = {
+  strict: true,
+  fields: [
+    { name: 'summary', type: 'string', required: true },
+    { name: 'count', type: 'integer', required: true },
+    { name: 'passed', type: 'boolean' },
+    { name: 'files', type: 'string[]' },
+  ],
+};
+
+function manifest(body: string): string {
+  return `Here is what I did.\n\n\`\`\`output-manifest\n${body}\n\`\`\`\n`;
+}
+
+describe('extractLastJsonBlock', () => {
+  it('prefers the last output-manifest block', () => {
+    const text = '```json\n{"a": 1}\n```\n\n' + manifest('{"b": 2}');
+    expect(extractLastJsonBlock(text)).toBe('{"b": 2}');
+  });
+
+  it('falls back to a json block', () => {
+    expect(extractLastJsonBlock('Result:\n```json\n{"a": 1}\n```')).toBe('{"a": 1}');
+  });
+
+  it('falls back to a trailing bare object', () => {
+    expect(extractLastJsonBlock('Done.\n{"a": 1}')).toBe('{"a": 1}');
+  });
+
+  it('returns null when there is no block', () => {
+    expect(extractLastJsonBlock('Just prose.')).toBeNull();
+  });
+});
+
+describe('validateOutput', () => {
+  it('accepts a valid manifest', () => {
+    const result = validateOutput(
+      manifest('{"summary": "ok", "count": 3, "passed": true, "files": ["a.ts"]}'),
+      schema
+    );
+    expect(result.errors).toEqual([]);
+    expect(result.data).toEqual({ summary: 'ok', count: 3, passed: true, files: ['a.ts'] });
+  });
+
+  it('reports a missing required field', () => {
+    const result = validateOutput(manifest('{"summary": "ok"}'), schema);
+    expect(result.errors).toEqual(['Missing required field "count"']);
+  });
+
+  it('reports a type mismatch', () => {
+    const result = validateOutput(manifest('{"summary": "ok", "count": "3", "files": [1]}'), schema);
+    expect(result.errors).toContain('Field "count" must be integer, got string');
+    expect(result.errors).toContain('Field "files" must be string[], got array');
+  });
+
+  it('reports a missing manifest', () => {
+    const result = validateOutput('No structured output here.', schema);
+    expect(result.data).toBeUndefined();
+    expect(result.errors[0]).toContain('No output-manifest block');
+  });
+
+  it('reports invalid JSON', () => {
+    const result = validateOutput(manifest('{"summary": '), schema);
+    expect(result.errors[0]).toContain('not valid JSON');
+  });
+
+  it('repairs trailing commas', () => {
+    const result = validateOutput(manifest('{"summary": "ok", "count": 2, "files": ["a.ts",],}'), schema);
+    expect(result.errors).toEqual([]);
+    expect(result.data).toEqual({ summary: 'ok', count: 2, files: ['a.ts'] });
+  });
+
+  it('repairs unquoted keys', () => {
+    const result = validateOutput(manifest('{summary: "ok: done", count: 1, passed: false}'), schema);
+    expect(result.errors).toEqual([]);
+    expect(result.data).toEqual({ summary: 'ok: done', count: 1, passed: false });
+  });
+});
+
+describe('repairJson', () => {
+  it('closes an unterminated string and brackets', () => {
+    expect(JSON.parse(repairJson('{"summary": "cut off, {x'))).toEqual({ summary: 'cut off, {x' });
+    expect(JSON.parse(repairJson('{"files": ["a", "b"'))).toEqual({ files: ['a', 'b'] });
+  });
+
+  it('leaves valid JSON alone', () => {
+    const json = '{"a": [1, {"b": "c,}"}], "d": null}';
+    expect(repairJson(json)).toBe(json);
+  });
+});
+
+describe('validateManifest', () => {
+  const nested: OutputSchema = {
+    fields: [
+      { name: 'meta', type: 'object', required: true },
+      { name: 'items', type: 'object[]' },
+    ],
+  };
+
+  it('keeps nested objects and arrays of objects', () => {
+    const result = validateOutput(
+      manifest('{"meta": {"owner": "ana", "tags": ["a"]}, "items": [{"id": 1}, {"id": 2}]}'),
+      nested
+    );
+    expect(result.errors).toEqual([]);
+    expect(result.data?.meta).toEqual({ owner: 'ana', tags: ['a'] });
+    expect(result.data?.items).toEqual([{ id: 1 }, { id: 2 }]);
+  });
+
+  it('rejects non-objects for object fields', () => {
+    expect(validateManifest({ meta: '{"owner": "ana"}', items: [{ id: 1 }, 2] }, nested)).toEqual([
+      'Field "meta" must be object, got string',
+      'Field "items" must be object[], got array',
+    ]);
+  });
+
+  it('ignores absent optional fields', () => {
+    expect(validateManifest({ summary: 'ok', count: 1 }, schema)).toEqual([]);
+  });
+
+  it('rejects non-integer numbers for integer fields', () => {
+    expect(validateManifest({ summary: 'ok', count: 1.5 }, schema)).toEqual([
+      'Field "count" must be integer, got number',
+    ]);
+  });
+});
+
+describe('buildOutputSchemaInstructions', () => {
+  it('lists every field with its type', () => {
+    const text = buildOutputSchemaInstructions(schema);
+    expect(text).toContain('output-manifest');
+    expect(text).toContain('- summary (string, required)');
+    expect(text).toContain('- files (string[], optional)');
+  });
+});
+
+describe('parseOpenManifest', () => {
+  it('returns top-level members whose value has closed', () => {
+    expect(parseOpenManifest('```output-manifest\n{"summary": "a, b", "files": ["x", "y"], "count": 1')).toEqual({
+      summary: 'a, b',
+      files: ['x', 'y'],
+    });
+  });
+
+  it('ignores closed blocks and text without a manifest', () => {
+    expect(parseOpenManifest(manifest('{"a": 1}'))).toBeNull();
+    expect(parseOpenManifest('{"a": 1,')).toBeNull();
+    expect(parseOpenManifest('```output-manifest\n{"a": "still go')).toBeNull();
+  });
+});
+
+describe('PartialOutputTracker', () => {
+  it('reports fields as their keys complete in a chunked manifest', () => {
+    const tracker = new PartialOutputTracker(schema);
+    const chunks = ['```output-manifest\n{"sum', 'mary": "done", "fil', 'es": ["a.ts"', ', "b.ts"], "co', 'unt": 3}\n```'];
+
+    expect(chunks.map(c => tracker.push(c))).toEqual([
+      null,
+      { summary: 'done' },
+      null,
+      { files: ['a.ts', 'b.ts'] },
+      { count: 3 },
+    ]);
+  });
+
+  it('reports fields once the manifest block completes mid-stream', () => {
+    const tracker = new PartialOutputTracker(schema);
+    const chunks = [
+      'Working on it...\n',
+      '```output-manifest\n{"summary": "ok",',
+      ' "count": 2}\n',
+      '```',
+      '\n\nAll done.',
+    ];
+
+    const results = chunks.map(c => tracker.push(c));
+    expect(results[0]).toBeNull();
+    expect(results[1]).toEqual({ summary: 'ok' });
+    expect(results[2]).toEqual({ count: 2 });
+    expect(results.slice(3)).toEqual([null, null]);
+  });
+
+  it('only reports fields that changed since the last update', () => {
+    const tracker = new PartialOutputTracker();
+    expect(tracker.push('```json\n{"a": 1}\n```')).toEqual({ a: 1 });
+    expect(tracker.push('\n```json\n{"a": 1, "b": [2]}\n```')).toEqual({ b: [2] });
+    expect(tracker.push('\n```json\n{"a": 1, "b": [2]}\n```')).toBeNull();
+  });
+
+  it('ignores fields outside the schema', () => {
+    const tracker = new PartialOutputTracker(schema);
+    expect(tracker.push(manifest('{"summary": "ok", "extra": true}'))).toEqual({ summary: 'ok' });
+  });
+});
@@ -0,0 +1,38 @@
+import { describe, it, expect, beforeAll, afterAll } from 'vitest';
+import { mkdtempSync, mkdirSync, rmSync, symlinkSync, writeFileSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { resolveWithin } from '../paths';
+
+let root: string;
+
+beforeAll(() => {
+  root = mkdtempSync(join(tmpdir(), 'cadre-paths-'));
+  mkdirSync(join(root, 'src'));
+  writeFileSync(join(root, 'src', 'main.ts'), '');
+  symlinkSync('/etc', join(root, 'etc-link'));
+});
+
+afterAll(() => {
+  rmSync(root, { recursive: true, force: true });
+});
+
+describe('resolveWithin', () => {
+  it('resolves paths inside the root', () => {
+    expect(resolveWithin(root, 'src/main.ts')).toBe(join(root, 'src', 'main.ts'));
+    expect(resolveWithin(root, './src/../src/main.ts')).toBe(join(root, 'src', 'main.ts'));
+    expect(resolveWithin(root, 'new/file.md')).toBe(join(root, 'new', 'file.md'));
+  });
+
+  it('rejects absolute paths and .. escapes', () => {
+    expect(resolveWithin(root, '/etc/passwd')).toBeNull();
+    expect(resolveWithin(root, '../outside')).toBeNull();
+    // Sibling sharing the root's name as a prefix
+    expect(resolveWithin(join(root, 'src'), '../src-other/x')).toBeNull();
+  });
+
+  it('rejects symlinks that point outside the root', () => {
+    expect(resolveWithin(root, 'etc-link/hostname')).toBeNull();
+    expect(resolveWithin(root, 'etc-link/not-there/x')).toBeNull();
+  });
+});
@@ -0,0 +1,47 @@
+import { describe, it, expect } from 'vitest';
+import { spawn } from 'child_process';
+import { existsSync, readFileSync } from 'fs';
+import { killProcessGroup, ownProcessGroup } from '../providers/process';
+
+// Zombies still answer kill(pid, 0), so read the process state instead
+function isRunning(pid: number): boolean {
+  const stat = `/proc/${pid}/stat`;
+  if (existsSync(stat)) {
+    try {
+      return !/\) [ZX] /.test(readFileSync(stat, 'utf-8'));
+    } catch {
+      return false;
+    }
+  }
+  try {
+    process.kill(pid, 0);
+    return true;
+  } catch {
+    return false;
+  }
+}
+
+async function waitFor(check: () => boolean, timeoutMs = 3000): Promise<boolean> {
+  const deadline = Date.now() + timeoutMs;
+  while (Date.now() < deadline) {
+    if (check()) return true;
+    await new Promise(r => setTimeout(r, 50));
+  }
+  return check();
+}
+
+describe.skipIf(!ownProcessGroup)('killProcessGroup', () => {
+  it('terminates the child and the processes it spawned', async () => {
+    const proc = spawn('sh', ['-c', 'sleep 30 & echo $!; wait'], { detached: true });
+    const grandchild = await new Promise<number>(resolve => {
+      proc.stdout.once('data', (data: Buffer) => resolve(Number(data.toString().trim())));
+    });
+    const exited = new Promise(resolve => proc.once('close', resolve));
+
+    expect(isRunning(grandchild)).toBe(true);
+    killProcessGroup(proc);
+
+    await exited;
+    expect(await waitFor(() => !isRunning(grandchild))).toBe(true);
+  });
+});
@@ -0,0 +1,102 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { ProviderError, isRetryableError } from '../providers/errors';
+import { Executor } from '../engine/executor';
+import type { WorkflowNode } from '../engine/types';
+
+const failures = vi.hoisted(() => ({ next: [] as Error[], calls: 0, partial: '' }));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'mock',
+    name: 'Mock',
+    async *stream() {
+      failures.calls++;
+      const error = failures.next.shift();
+      if (error && failures.partial) yield { type: 'text' as const, content: failures.partial };
+      if (error) throw error;
+      yield { type: 'text' as const, content: 'ok' };
+      yield { type: 'done' as const, content: '' };
+    },
+  }),
+}));
+
+const agent: WorkflowNode = {
+  id: 'a',
+  type: 'agent',
+  position: { x: 0, y: 0 },
+  data: { label: 'A', retries: 1 },
+};
+
+describe('isRetryableError', () => {
+  it('classifies provider errors by kind', () => {
+    expect(isRetryableError(new ProviderError('codex', 'exit', 'boom', { exitCode: 1 }))).toBe(true);
+    expect(isRetryableError(new ProviderError('codex', 'not-installed', 'missing'))).toBe(false);
+    expect(isRetryableError(new ProviderError('codex', 'cancelled', 'stop'))).toBe(false);
+  });
+
+  it('treats unknown errors as retryable', () => {
+    expect(isRetryableError(new Error('timeout'))).toBe(true);
+  });
+
+  it('keeps exit details', () => {
+    const error = new ProviderError('gemini', 'exit', 'rate limited', { exitCode: 2, stderr: 'rate limited' });
+    expect(error).toBeInstanceOf(Error);
+    expect(error.name).toBe('ProviderError');
+    expect(error.exitCode).toBe(2);
+    expect(error.stderr).toBe('rate limited');
+  });
+});
+
+describe('Executor retries', () => {
+  beforeEach(() => {
+    failures.next = [];
+    failures.calls = 0;
+    failures.partial = '';
+  });
+
+  it('does not retry a missing CLI', async () => {
+    failures.next = [new ProviderError('mock', 'not-installed', 'Mock CLI not found')];
+
+    const result = await new Executor([agent], [], {}).execute();
+
+    expect(failures.calls).toBe(1);
+    expect(result.nodeStates.a.status).toBe('failed');
+    expect(result.nodeStates.a.error).toBe('Mock CLI not found');
+  });
+
+  it('retries a failed exit', async () => {
+    failures.next = [new ProviderError('mock', 'exit', 'exited with code 1', { exitCode: 1 })];
+    const retries: unknown[] = [];
+
+    const result = await new Executor([agent], [], {
+      onEvent: (event) => { if (event.type === 'node-retry') retries.push(event.data); },
+    }).execute();
+
+    expect(failures.calls).toBe(2);
+    expect(result.nodeStates.a.status).toBe('completed');
+    expect(result.nodeStates.a.attempts).toBe(2);
+    expect(retries).toEqual([{ attempt: 2, error: 'exited with code 1', delayMs: 1000 }]);
+  });
+
+  it('does not retry once output has streamed', async () => {
+    failures.next = [new ProviderError('mock', 'exit', 'exited with code 1', { exitCode: 1 })];
+    failures.partial = 'half an answer';
+
+    const result = await new Executor([agent], [], {}).execute();
+
+    expect(failures.calls).toBe(1);
+    expect(result.nodeStates.a.status).toBe('failed');
+    expect(result.nodeStates.a.output).toBe('half an answer');
+  });
+
+  it('retries after streamed output when the node allows it', async () => {
+    failures.next = [new ProviderError('mock', 'exit', 'exited with code 1', { exitCode: 1 })];
+    failures.partial = 'half an answer';
+
+    const result = await new Executor([{ ...agent, data: { ...agent.data, retryAfterOutput: true } }], [], {}).execute();
+
+    expect(failures.calls).toBe(2);
+    expect(result.nodeStates.a.status).toBe('completed');
+    expect(result.nodeStates.a.output).toBe('ok');
+  });
+});
@@ -0,0 +1,56 @@
+import { describe, it, expect } from 'vitest';
+import { cachedCheck, runReadinessChecks } from '../readiness';
+
+describe('runReadinessChecks', () => {
+  it('is ready when every check passes', async () => {
+    const report = await runReadinessChecks({
+      database: async () => {},
+      providers: async () => {},
+    });
+
+    expect(report).toEqual({ ready: true, checks: { database: { status: 'ok' }, providers: { status: 'ok' } } });
+  });
+
+  it('names the failing check', async () => {
+    const report = await runReadinessChecks({
+      database: async () => { throw new Error('connection refused'); },
+      providers: async () => {},
+    });
+
+    expect(report.ready).toBe(false);
+    expect(report.checks.database).toEqual({ status: 'error', error: 'connection refused' });
+    expect(report.checks.providers.status).toBe('ok');
+  });
+
+  it('fails a check that hangs', async () => {
+    const report = await runReadinessChecks({ database: () => new Promise(() => {}) }, 20);
+
+    expect(report.checks.database).toEqual({ status: 'error', error: 'Timed out after 20ms' });
+  });
+});
+
+describe('cachedCheck', () => {
+  it('reuses the outcome until the TTL passes', async () => {
+    let clock = 0;
+    let calls = 0;
+    const check = cachedCheck(async () => { calls++; throw new Error('missing'); }, 1000, () => clock);
+
+    await expect(check()).rejects.toThrow('missing');
+    clock = 999;
+    await expect(check()).rejects.toThrow('missing');
+    expect(calls).toBe(1);
+
+    clock = 1000;
+    await expect(check()).rejects.toThrow('missing');
+    expect(calls).toBe(2);
+  });
+
+  it('shares a pending run instead of starting another', async () => {
+    let calls = 0;
+    const check = cachedCheck(() => { calls++; return new Promise(() => {}); }, 1000, () => 0);
+
+    void check();
+    void check();
+    expect(calls).toBe(1);
+  });
+});
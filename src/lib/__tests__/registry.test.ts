@@ -0,0 +1,32 @@
+import { describe, it, expect } from 'vitest';
+import { getProvider, listProviders, registerProvider } from '../providers/registry';
+import type { CodingAgentProvider } from '../providers/base';
+
+function stub(id: string, name = id): CodingAgentProvider {
+  return {
+    id,
+    name,
+    async *stream() {
+      yield { type: 'done' as const, content: '' };
+    },
+    validateCli: async () => true,
+  };
+}
+
+describe('registerProvider', () => {
+  it('registers a new provider for lookup and listing', () => {
+    const provider = stub('custom-agent');
+    registerProvider(provider);
+
+    expect(getProvider('custom-agent')).toBe(provider);
+    expect(listProviders().map(p => p.id)).toContain('custom-agent');
+  });
+
+  it('rejects duplicate ids unless overriding', () => {
+    expect(() => registerProvider(stub('codex'))).toThrow('Provider "codex" is already registered');
+
+    const replacement = stub('codex', 'Patched Codex');
+    registerProvider(replacement, { override: true });
+    expect(getProvider('codex')).toBe(replacement);
+  });
+});
@@ -0,0 +1,99 @@
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest';
+import { mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { ReplayProvider, requestKey } from '../providers/replay';
+import { ProviderError } from '../providers/errors';
+import { countPromptTokens } from '../engine/context-window';
+import type { CodingAgentProvider } from '../providers/base';
+import type { ProviderMessage, StreamChunk } from '@/types/provider';
+
+function scriptedProvider(): CodingAgentProvider & { calls: number } {
+  const provider = {
+    id: 'mock',
+    name: 'Mock',
+    calls: 0,
+    async *stream(messages: ProviderMessage[]): AsyncGenerator<StreamChunk> {
+      provider.calls++;
+      yield { type: 'text', content: `answer ${provider.calls}: ${messages[0].content}` };
+      yield { type: 'done', content: '', tokens: { input: 3, output: 2 } };
+    },
+    validateCli: vi.fn().mockResolvedValue(true),
+  };
+  return provider;
+}
+
+async function collect(provider: CodingAgentProvider, content: string): Promise<StreamChunk[]> {
+  const chunks: StreamChunk[] = [];
+  for await (const chunk of provider.stream([{ role: 'user', content }], { maxTurns: 5 }, '')) {
+    chunks.push(chunk);
+  }
+  return chunks;
+}
+
+describe('ReplayProvider', () => {
+  let dir: string;
+  let path: string;
+
+  beforeEach(() => {
+    dir = mkdtempSync(join(tmpdir(), 'cadre-replay-'));
+    path = join(dir, 'recording.jsonl');
+  });
+
+  afterEach(() => {
+    rmSync(dir, { recursive: true, force: true });
+  });
+
+  it('replays a recorded run without calling the provider', async () => {
+    const live = scriptedProvider();
+    const recorder = new ReplayProvider(live, path, 'record');
+    const recorded = [await collect(recorder, 'plan'), await collect(recorder, 'build')];
+
+    expect(live.calls).toBe(2);
+    expect(readFileSync(path, 'utf-8').trim().split('\n')).toHaveLength(2);
+
+    const offline = scriptedProvider();
+    const replayer = new ReplayProvider(offline, path, 'replay');
+    const replayed = [await collect(replayer, 'plan'), await collect(replayer, 'build')];
+
+    expect(replayed).toEqual(recorded);
+    expect(offline.calls).toBe(0);
+  });
+
+  it('replays repeated identical requests in recorded order', async () => {
+    const recorder = new ReplayProvider(scriptedProvider(), path, 'record');
+    await collect(recorder, 'same');
+    await collect(recorder, 'same');
+
+    const replayer = new ReplayProvider(scriptedProvider(), path, 'replay');
+    expect((await collect(replayer, 'same'))[0].content).toBe('answer 1: same');
+    expect((await collect(replayer, 'same'))[0].content).toBe('answer 2: same');
+  });
+
+  it('fails on a cache miss', async () => {
+    const replayer = new ReplayProvider(scriptedProvider(), path, 'replay');
+    const error = await collect(replayer, 'unknown').catch((e: unknown) => e);
+
+    expect(error).toBeInstanceOf(ProviderError);
+    expect((error as ProviderError).kind).toBe('replay-miss');
+    expect((error as ProviderError).retryable).toBe(false);
+  });
+
+  it('forwards the token counter of the wrapped provider', async () => {
+    const messages: ProviderMessage[] = [{ role: 'user', content: 'hi' }];
+    const countTokens = vi.fn(async () => 42);
+
+    expect(await countPromptTokens(new ReplayProvider({ ...scriptedProvider(), countTokens }, path, 'replay'), messages)).toBe(42);
+    expect(countTokens).toHaveBeenCalledWith(messages, {});
+    expect(new ReplayProvider(scriptedProvider(), path, 'replay').countTokens).toBeUndefined();
+  });
+});
+
+describe('requestKey', () => {
+  it('ignores the workspace path but not the prompt', () => {
+    const messages: ProviderMessage[] = [{ role: 'user', content: 'hi' }];
+    expect(requestKey('codex', messages, { workspacePath: '/a' })).toBe(requestKey('codex', messages, { workspacePath: '/b' }));
+    expect(requestKey('codex', messages, {})).not.toBe(requestKey('codex', [{ role: 'user', content: 'bye' }], {}));
+    expect(requestKey('codex', messages, {})).not.toBe(requestKey('gemini', messages, {}));
+  });
+});
@@ -0,0 +1,113 @@
+import { describe, it, expect } from 'vitest';
+import { buildRunReport, compareReports, formatDuration, renderReportMarkdown } from '../engine/report';
+import type { WorkflowNode } from '../engine/types';
+
+const nodes: WorkflowNode[] = [
+  { id: 'in', type: 'input', position: { x: 0, y: 0 }, data: { label: 'Input' } },
+  { id: 'plan', type: 'agent', position: { x: 0, y: 100 }, data: { label: 'Planner' } },
+  { id: 'out', type: 'output', position: { x: 0, y: 200 }, data: { label: 'Output' } },
+];
+
+function source() {
+  return {
+    id: '11111111-2222-3333-4444-555555555555',
+    workflowId: 'wf-1',
+    workflowName: 'Release notes',
+    status: 'completed',
+    startedAt: '2026-01-01T00:00:00.000Z',
+    completedAt: '2026-01-01T00:01:05.000Z',
+    context: {
+      input: 'v1.2.0',
+      node_plan_output: 'Plan text',
+      gate_g_decision: 'approved',
+      _workspacePath: '/tmp/ws',
+      output: 'Final notes',
+    },
+    nodeStates: {
+      out: { status: 'completed' as const, startedAt: '2026-01-01T00:01:00.000Z', completedAt: '2026-01-01T00:01:00.500Z' },
+      in: { status: 'completed' as const, startedAt: '2026-01-01T00:00:00.000Z', completedAt: '2026-01-01T00:00:00.010Z' },
+      plan: {
+        status: 'completed' as const,
+        startedAt: '2026-01-01T00:00:01.000Z',
+        completedAt: '2026-01-01T00:00:59.000Z',
+        output: 'Plan text',
+        tokens: { input: 100, output: 50 },
+      },
+    },
+    tokenUsage: { input: 100, output: 50, cost: 0.0012 },
+    nodes,
+  };
+}
+
+describe('buildRunReport', () => {
+  it('keeps only user-supplied context keys as inputs', () => {
+    const report = buildRunReport(source());
+    expect(report.inputs).toEqual({ input: 'v1.2.0' });
+  });
+
+  it('orders nodes by graph order and computes durations', () => {
+    const report = buildRunReport(source());
+    expect(report.nodes.map(n => n.id)).toEqual(['in', 'plan', 'out']);
+    expect(report.nodes[1].durationMs).toBe(58000);
+    expect(report.durationMs).toBe(65000);
+    expect(report.output).toBe('Final notes');
+  });
+
+  it('includes states for nodes missing from the graph', () => {
+    const report = buildRunReport({ ...source(), nodes: nodes.slice(0, 1) });
+    expect(report.nodes.map(n => n.id)).toEqual(['in', 'out', 'plan']);
+    expect(report.nodes[2].label).toBe('plan');
+  });
+});
+
+describe('compareReports', () => {
+  it('reports changed run and node fields', () => {
+    const a = buildRunReport(source());
+    const base = source();
+    const b = buildRunReport({
+      ...base,
+      id: 'other',
+      context: { ...base.context, output: 'Revised notes' },
+      nodeStates: {
+        ...base.nodeStates,
+        plan: { ...base.nodeStates.plan, output: 'Revised plan', tokens: { input: 120, output: 60 } },
+      },
+      tokenUsage: { input: 120, output: 60, cost: 0.0015 },
+    });
+
+    const diff = compareReports(a, b);
+    expect(diff.a.runId).toBe(a.runId);
+    expect(diff.b.runId).toBe('other');
+    expect(diff.changed).toEqual(['output', 'totalTokens']);
+    expect(diff.nodes.find(n => n.id === 'plan')!.changed).toEqual(['output', 'tokens']);
+    expect(diff.nodes.find(n => n.id === 'in')!.changed).toEqual([]);
+  });
+
+  it('marks nodes present in only one run', () => {
+    const a = buildRunReport({ ...source(), nodeStates: { in: source().nodeStates.in } });
+    const b = buildRunReport({ ...source(), nodeStates: { out: source().nodeStates.out } });
+    const diff = compareReports(a, b);
+    expect(diff.nodes.map(n => [n.id, n.onlyIn])).toEqual([['in', 'a'], ['out', 'b']]);
+  });
+});
+
+describe('renderReportMarkdown', () => {
+  it('renders the summary sections', () => {
+    const md = renderReportMarkdown(buildRunReport(source()));
+    expect(md).toContain('# Release notes — run 11111111');
+    expect(md).toContain('- **Duration:** 1m 5s');
+    expect(md).toContain('| Planner | agent | ✅ completed | 58.0s | 150 |');
+    expect(md).toContain('## Timeline');
+    expect(md).toContain('### Planner');
+    expect(md).toContain('## Final Output\n\nFinal notes');
+  });
+});
+
+describe('formatDuration', () => {
+  it('formats across units', () => {
+    expect(formatDuration(undefined)).toBe('-');
+    expect(formatDuration(250)).toBe('250ms');
+    expect(formatDuration(1500)).toBe('1.5s');
+    expect(formatDuration(125000)).toBe('2m 5s');
+  });
+});
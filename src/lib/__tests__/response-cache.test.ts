@@ -0,0 +1,57 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { Executor } from '../engine/executor';
+import { clearResponseCache, getCachedResponse, responseCacheKey, setCachedResponse } from '../engine/response-cache';
+import type { WorkflowNode } from '../engine/types';
+
+const calls = vi.hoisted(() => ({ count: 0 }));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'counting',
+    name: 'Counting',
+    async *stream() {
+      calls.count++;
+      yield { type: 'text' as const, content: `answer ${calls.count}` };
+      yield { type: 'done' as const, content: '', tokens: { input: 10, output: 5 } };
+    },
+  }),
+}));
+
+function agent(data: Partial<WorkflowNode['data']> = {}): WorkflowNode {
+  return { id: 'a', type: 'agent', position: { x: 0, y: 0 }, data: { label: 'a', systemPrompt: 'Summarize', ...data } };
+}
+
+describe('response cache', () => {
+  beforeEach(() => {
+    calls.count = 0;
+    clearResponseCache();
+  });
+
+  it('expires entries after the TTL', () => {
+    const key = responseCacheKey('claude-code', [{ role: 'user', content: 'hi' }]);
+    setCachedResponse(key, 'hello', -1);
+    expect(getCachedResponse(key)).toBeUndefined();
+  });
+
+  it('keys on the prompt', () => {
+    const a = responseCacheKey('claude-code', [{ role: 'user', content: 'hi' }]);
+    const b = responseCacheKey('claude-code', [{ role: 'user', content: 'hello' }]);
+    expect(a).not.toBe(b);
+  });
+
+  it('serves a repeat run from the cache without calling the provider', async () => {
+    const first = await new Executor([agent({ cacheResponses: true })], [], {}).execute();
+    const second = await new Executor([agent({ cacheResponses: true })], [], {}).execute();
+
+    expect(calls.count).toBe(1);
+    expect(second.nodeStates.a.output).toBe(first.nodeStates.a.output);
+    expect(second.nodeStates.a.cached).toBe(true);
+  });
+
+  it('does not cache nodes without cacheResponses', async () => {
+    await new Executor([agent()], [], {}).execute();
+    await new Executor([agent()], [], {}).execute();
+
+    expect(calls.count).toBe(2);
+  });
+});
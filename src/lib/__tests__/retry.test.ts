@@ -0,0 +1,63 @@
+import { describe, it, expect } from 'vitest';
+import { computeBackoff, DEFAULT_RETRY_POLICY, summarizeRetries } from '../engine/retry';
+import type { RetryPolicy } from '../engine/retry';
+
+function seeded(seed: number): () => number {
+  // mulberry32
+  return () => {
+    seed |= 0;
+    seed = (seed + 0x6d2b79f5) | 0;
+    let t = Math.imul(seed ^ (seed >>> 15), 1 | seed);
+    t = (t + Math.imul(t ^ (t >>> 7), 61 | t)) ^ t;
+    return ((t ^ (t >>> 14)) >>> 0) / 4294967296;
+  };
+}
+
+const policy = (jitter: RetryPolicy['jitter']): RetryPolicy => ({ ...DEFAULT_RETRY_POLICY, jitter });
+
+describe('computeBackoff', () => {
+  it('keeps the default exponential backoff without jitter', () => {
+    expect([0, 1, 2, 3].map(a => computeBackoff(a))).toEqual([1000, 2000, 4000, 8000]);
+  });
+
+  it('caps the delay at maxDelayMs', () => {
+    expect(computeBackoff(10, { ...DEFAULT_RETRY_POLICY, maxDelayMs: 5000 })).toBe(5000);
+  });
+
+  it('keeps proportional jitter within the configured fraction', () => {
+    const random = seeded(42);
+    for (let attempt = 0; attempt < 5; attempt++) {
+      const base = 1000 * Math.pow(2, attempt);
+      for (let i = 0; i < 50; i++) {
+        const delay = computeBackoff(attempt, policy('proportional'), random);
+        expect(delay).toBeGreaterThanOrEqual(base * 0.8);
+        expect(delay).toBeLessThanOrEqual(base * 1.2);
+      }
+    }
+  });
+
+  it('spreads full jitter between zero and the computed backoff', () => {
+    const random = seeded(7);
+    const delays = Array.from({ length: 200 }, () => computeBackoff(2, policy('full'), random));
+    expect(Math.min(...delays)).toBeGreaterThanOrEqual(0);
+    expect(Math.max(...delays)).toBeLessThan(4000);
+    expect(Math.min(...delays)).toBeLessThan(1000);
+  });
+
+  it('maps the random source onto the range edges', () => {
+    expect(computeBackoff(1, policy('full'), () => 0)).toBe(0);
+    expect(computeBackoff(1, policy('proportional'), () => 0)).toBe(1600);
+    expect(computeBackoff(1, policy('proportional'), () => 0.5)).toBe(2000);
+  });
+});
+
+describe('summarizeRetries', () => {
+  it('counts extra attempts and how retried nodes ended', () => {
+    expect(summarizeRetries({
+      a: { status: 'completed' },
+      b: { status: 'completed', attempts: 2 },
+      c: { status: 'failed', attempts: 3 },
+      d: { status: 'failed' },
+    })).toEqual({ retries: 3, recovered: 1, exhausted: 1 });
+  });
+});
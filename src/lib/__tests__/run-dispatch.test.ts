@@ -0,0 +1,109 @@
+import { describe, it, expect, vi } from 'vitest';
+import type { SQL } from 'drizzle-orm';
+import { dispatchQueuedRuns } from '../engine/run-simple';
+
+interface Row {
+  id: string;
+  status: string;
+  priority: number;
+  startedAt: Date;
+  workflowId: string;
+  userId: string;
+  inputs: Record<string, string>;
+  overrides: Record<string, unknown>;
+}
+
+const queue = vi.hoisted(() => ({
+  rows: [] as Row[],
+  started: [] as string[],
+  finish: new Map<string, () => void>(),
+}));
+
+vi.mock('../config', () => ({
+  getConfig: () => ({ engine: { maxConcurrentRuns: 1, runQueueSize: 10, secretsDir: '' } }),
+}));
+
+vi.mock('fs', async (importOriginal) => ({ ...(await importOriginal<typeof import('fs')>()), mkdirSync: vi.fn() }));
+
+vi.mock('../engine/executor', () => ({
+  Executor: class {
+    constructor(_nodes: unknown, _edges: unknown, private options: { runId: string }) {}
+    execute() {
+      queue.started.push(this.options.runId);
+      return new Promise<void>(resolve => queue.finish.set(this.options.runId, resolve));
+    }
+    abort() {}
+  },
+}));
+
+// Just enough of drizzle's query builder for the dispatcher: queued runs, the workflow, and the claim update
+vi.mock('../db', async () => {
+  const { runs } = await import('../db/schema');
+  const { PgDialect } = await import('drizzle-orm/pg-core');
+  const dialect = new PgDialect();
+  const workflow = {
+    id: 'wf',
+    name: 'wf',
+    userId: 'user',
+    graphData: { nodes: [{ id: 'a', type: 'agent', position: { x: 0, y: 0 }, data: { label: 'a' } }], edges: [] },
+    variables: {},
+    timeout: null,
+  };
+  return {
+    db: {
+      select: () => ({
+        from: (table: unknown) => ({
+          where: async () => (table === runs ? queue.rows.filter(r => r.status === 'queued') : [workflow]),
+        }),
+      }),
+      update: () => ({
+        set: (values: Partial<Row>) => ({
+          where: (where: SQL) => ({
+            returning: async () => {
+              const [id, status] = dialect.sqlToQuery(where).params;
+              const row = queue.rows.find(r => r.id === id && r.status === status);
+              if (!row) return [];
+              Object.assign(row, values);
+              return [row];
+            },
+          }),
+        }),
+      }),
+    },
+  };
+});
+
+function queued(id: string, priority: number, queuedAt: number): Row {
+  return {
+    id,
+    status: 'queued',
+    priority,
+    startedAt: new Date(queuedAt),
+    workflowId: 'wf',
+    userId: 'user',
+    inputs: {},
+    overrides: {},
+  };
+}
+
+describe('dispatchQueuedRuns', () => {
+  it('starts queued runs one at a time by priority, then age', async () => {
+    queue.rows = [
+      queued('low', 0, 1),
+      queued('urgent', 5, 4),
+      queued('normal-old', 1, 2),
+      queued('normal-new', 1, 3),
+    ];
+
+    await dispatchQueuedRuns();
+    expect(queue.started).toEqual(['urgent']);
+
+    for (const [running, next] of [['urgent', 'normal-old'], ['normal-old', 'normal-new'], ['normal-new', 'low']]) {
+      queue.finish.get(running)!();
+      await vi.waitFor(() => expect(queue.started.at(-1)).toBe(next));
+    }
+
+    expect(queue.started).toEqual(['urgent', 'normal-old', 'normal-new', 'low']);
+    expect(queue.rows.every(r => r.status === 'running')).toBe(true);
+  });
+});
@@ -0,0 +1,68 @@
+import { describe, it, expect } from 'vitest';
+import { Executor } from '../engine/executor';
+import { toStoredEvent, type StoredRunEvent } from '../engine/run-events';
+import type { WorkflowNode } from '../engine/types';
+
+const node = (id: string, type: WorkflowNode['type'], data: Partial<WorkflowNode['data']> = {}): WorkflowNode => ({
+  id,
+  type,
+  position: { x: 0, y: 0 },
+  data: { label: id, ...data },
+});
+
+describe('toStoredEvent', () => {
+  it('skips streamed output chunks', () => {
+    expect(toStoredEvent({ type: 'node-output', nodeId: 'a', data: { chunk: 'x' }, timestamp: new Date() }, 0)).toBeNull();
+  });
+
+  it('drops payloads already kept on the run row', () => {
+    const timestamp = new Date();
+    expect(toStoredEvent({ type: 'node-complete', nodeId: 'a', data: { output: 'long' }, timestamp }, 3))
+      .toEqual({ seq: 3, type: 'node-complete', nodeId: 'a', data: {}, createdAt: timestamp });
+    expect(toStoredEvent({ type: 'run-complete', data: { status: 'completed', context: {} }, timestamp }, 4)?.data)
+      .toEqual({ status: 'completed' });
+  });
+
+  it('records a completed run in emission order', async () => {
+    const stored: StoredRunEvent[] = [];
+    let seq = 0;
+    await new Executor(
+      [node('in', 'input', { defaultValue: 'hi' }), node('t', 'transform', { template: '{{input}}!' }), node('out', 'output')],
+      [{ id: 'e1', source: 'in', target: 't' }, { id: 'e2', source: 't', target: 'out' }],
+      {
+        onEvent: (event) => {
+          const s = toStoredEvent(event, seq);
+          if (s) { stored.push(s); seq++; }
+        },
+      }
+    ).execute();
+
+    expect(stored.map(e => e.seq)).toEqual(stored.map((_, i) => i));
+    expect(stored.map(e => `${e.type}:${e.nodeId ?? ''}`)).toEqual([
+      'node-start:in', 'node-complete:in', 'run-progress:',
+      'node-start:t', 'node-complete:t', 'run-progress:',
+      'node-start:out', 'node-complete:out', 'run-progress:',
+      'run-complete:',
+    ]);
+    expect(stored[stored.length - 1].data).toEqual({ status: 'completed' });
+  });
+});
+
+describe('run progress', () => {
+  it('reports the share of settled nodes after each node', async () => {
+    const progress: unknown[] = [];
+    const ids = ['a', 'b', 'c', 'd'];
+    await new Executor(
+      ids.map(id => node(id, 'transform', { template: id })),
+      ids.slice(1).map((id, i) => ({ id: `e${i}`, source: ids[i], target: id })),
+      { onEvent: (event) => { if (event.type === 'run-progress') progress.push(event.data); } }
+    ).execute();
+
+    expect(progress).toEqual([
+      { completed: 1, total: 4, percent: 25 },
+      { completed: 2, total: 4, percent: 50 },
+      { completed: 3, total: 4, percent: 75 },
+      { completed: 4, total: 4, percent: 100 },
+    ]);
+  });
+});
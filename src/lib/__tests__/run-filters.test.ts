@@ -0,0 +1,18 @@
+import { describe, it, expect } from 'vitest';
+import { PgDialect } from 'drizzle-orm/pg-core';
+import { runLabelCondition } from '../run-filters';
+
+describe('runLabelCondition', () => {
+  it('uses jsonb containment so the labels GIN index applies', () => {
+    const query = new PgDialect().sqlToQuery(runLabelCondition({ key: 'env', value: 'prod' }));
+
+    expect(query.sql).toMatch(/"labels" @> \$1::jsonb$/);
+    expect(query.params).toEqual(['{"env":"prod"}']);
+  });
+
+  it('encodes keys and values as JSON rather than SQL', () => {
+    const query = new PgDialect().sqlToQuery(runLabelCondition({ key: "team'", value: 'a"b' }));
+
+    expect(JSON.parse(query.params[0] as string)).toEqual({ "team'": 'a"b' });
+  });
+});
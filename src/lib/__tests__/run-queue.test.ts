@@ -0,0 +1,32 @@
+import { describe, it, expect } from 'vitest';
+import { orderQueue, RunQueueFullError } from '../engine/run-queue';
+
+function queued(id: string, priority: number, minute: number) {
+  return { id, priority, startedAt: new Date(Date.UTC(2026, 0, 1, 0, minute)) };
+}
+
+describe('orderQueue', () => {
+  it('dispatches higher priority first and arrival order within a priority', () => {
+    const order = orderQueue([
+      queued('low-early', -5, 0),
+      queued('normal-late', 0, 3),
+      queued('high', 10, 4),
+      queued('normal-early', 0, 1),
+      queued('low-late', -5, 2),
+    ]).map(r => r.id);
+
+    expect(order).toEqual(['high', 'normal-early', 'normal-late', 'low-early', 'low-late']);
+  });
+
+  it('does not reorder the input', () => {
+    const input = [queued('a', 0, 1), queued('b', 1, 0)];
+    orderQueue(input);
+    expect(input.map(r => r.id)).toEqual(['a', 'b']);
+  });
+});
+
+describe('RunQueueFullError', () => {
+  it('names the queue limit rather than the concurrency limit', () => {
+    expect(new RunQueueFullError(20).message).toBe('Run queue is full (limit 20 queued runs)');
+  });
+});
@@ -0,0 +1,76 @@
+import { describe, it, expect } from 'vitest';
+import { Graph } from '../engine/graph';
+import { Scheduler } from '../engine/scheduler';
+import { RunContext } from '../engine/context';
+import { Executor, resolveConcurrency, DEFAULT_CONCURRENCY, MAX_CONCURRENCY } from '../engine/executor';
+import type { WorkflowEdge, WorkflowNode } from '../engine/types';
+
+function transform(id: string, label: string, priority?: number): WorkflowNode {
+  return { id, type: 'transform', position: { x: 0, y: 0 }, data: { label, template: id, priority } };
+}
+
+const nodes: WorkflowNode[] = [
+  { id: 'in', type: 'input', position: { x: 0, y: 0 }, data: { label: 'Input' } },
+  transform('low', 'Low', 1),
+  transform('high', 'High', 10),
+  transform('mid-b', 'Mid B', 5),
+  transform('mid-a', 'Mid A', 5),
+];
+
+const edges: WorkflowEdge[] = ['low', 'high', 'mid-b', 'mid-a'].map(target => ({
+  id: `in-${target}`,
+  source: 'in',
+  target,
+}));
+
+describe('Scheduler', () => {
+  it('orders ready nodes by descending priority, then label', () => {
+    const scheduler = new Scheduler(new Graph(nodes, edges));
+    const context = new RunContext();
+    context.setNodeState('in', { status: 'completed' });
+    scheduler.markCompleted('in');
+
+    const batch = scheduler.getNextBatch(context);
+    expect(batch?.nodeIds).toEqual(['high', 'mid-a', 'mid-b', 'low']);
+    expect(batch?.isParallel).toBe(true);
+  });
+});
+
+describe('Executor concurrency', () => {
+  it('runs queued nodes in priority order with a concurrency of 1', async () => {
+    const events: string[] = [];
+    const executor = new Executor(nodes, edges, {
+      concurrency: 1,
+      onEvent: (event) => {
+        if (event.nodeId === 'in') return;
+        if (event.type === 'node-start') events.push(`start:${event.nodeId}`);
+        if (event.type === 'node-complete') events.push(`done:${event.nodeId}`);
+      },
+    });
+
+    const result = await executor.execute();
+    expect(result.status).toBe('completed');
+    expect(events).toEqual([
+      'start:high', 'done:high',
+      'start:mid-a', 'done:mid-a',
+      'start:mid-b', 'done:mid-b',
+      'start:low', 'done:low',
+    ]);
+  });
+});
+
+describe('resolveConcurrency', () => {
+  it('uses the default when unset or zero', () => {
+    expect(resolveConcurrency()).toBe(DEFAULT_CONCURRENCY);
+    expect(resolveConcurrency(0)).toBe(DEFAULT_CONCURRENCY);
+  });
+
+  it('caps large values', () => {
+    expect(resolveConcurrency(10_000)).toBe(MAX_CONCURRENCY);
+  });
+
+  it('rejects negative values', () => {
+    expect(() => resolveConcurrency(-1)).toThrow('Invalid concurrency -1');
+    expect(() => new Executor(nodes, edges, { concurrency: -2 })).toThrow('Invalid concurrency -2');
+  });
+});
@@ -0,0 +1,132 @@
+import { describe, it, expect, vi, beforeAll, afterAll } from 'vitest';
+import { mkdtempSync, rmSync, writeFileSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { isSecretRef, resolveSecret, resolveSecretVariables, SecretError } from '../secrets';
+import { Executor } from '../engine/executor';
+import { buildRunReport } from '../engine/report';
+import type { WorkflowNode } from '../engine/types';
+import type { ProviderMessage } from '@/types/provider';
+
+const seen = vi.hoisted(() => ({ system: '' as string | undefined, fail: false }));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'echo',
+    name: 'Echo',
+    async *stream(messages: ProviderMessage[]) {
+      seen.system = messages.find(m => m.role === 'system')?.content;
+      if (seen.fail) throw new Error(`CLI exited: could not use prompt "${seen.system}"`);
+      yield { type: 'text' as const, content: 'ok' };
+    },
+  }),
+}));
+
+const env = { CADRE_SECRET_TOKEN: 's3cr3t', DATABASE_URL: 'postgres://db' };
+let dir: string;
+
+beforeAll(() => {
+  dir = mkdtempSync(join(tmpdir(), 'cadre-secrets-'));
+  writeFileSync(join(dir, 'api-key'), 'from-file\n');
+});
+
+afterAll(() => {
+  rmSync(dir, { recursive: true, force: true });
+});
+
+describe('resolveSecret', () => {
+  it('recognizes secret references', () => {
+    expect(isSecretRef('secret://env/CADRE_SECRET_TOKEN')).toBe(true);
+    expect(isSecretRef('plain value')).toBe(false);
+  });
+
+  it('resolves prefixed env vars and files within the secrets directory', () => {
+    expect(resolveSecret('secret://env/CADRE_SECRET_TOKEN', { secretsDir: '', env })).toBe('s3cr3t');
+    expect(resolveSecret('secret://file/api-key', { secretsDir: dir, env })).toBe('from-file');
+  });
+
+  it('rejects unprefixed env vars, missing values and paths outside the directory', () => {
+    expect(() => resolveSecret('secret://env/DATABASE_URL', { secretsDir: '', env })).toThrow(SecretError);
+    expect(() => resolveSecret('secret://env/CADRE_SECRET_MISSING', { secretsDir: '', env })).toThrow(/not set/);
+    expect(() => resolveSecret('secret://file/../etc/passwd', { secretsDir: dir, env })).toThrow(/outside/);
+    expect(() => resolveSecret('secret://file/api-key', { secretsDir: '', env })).toThrow(/disabled/);
+  });
+});
+
+describe('secret variables at run time', () => {
+  it('interpolates secrets without storing them in the run context', async () => {
+    const { variables, secrets } = resolveSecretVariables(
+      { team: 'platform', token: 'secret://env/CADRE_SECRET_TOKEN' },
+      { secretsDir: '', env }
+    );
+    expect(variables).toEqual({ team: 'platform' });
+
+    const node: WorkflowNode = {
+      id: 'a',
+      type: 'agent',
+      position: { x: 0, y: 0 },
+      data: { label: 'a', systemPrompt: '{{team}} uses {{token}}' },
+    };
+    const state = await new Executor([node], [], { variables, secrets }).execute();
+
+    expect(seen.system).toBe('platform uses s3cr3t');
+    expect(JSON.stringify(state.context)).not.toContain('s3cr3t');
+    const report = buildRunReport({
+      id: 'r',
+      workflowId: 'w',
+      workflowName: 'w',
+      status: state.status,
+      startedAt: state.startedAt,
+      context: state.context,
+      nodeStates: state.nodeStates,
+    });
+    expect(report.inputs).toEqual({ team: 'platform' });
+  });
+
+  it('masks secrets a transform writes into its output', async () => {
+    const { variables, secrets } = resolveSecretVariables(
+      { token: 'secret://env/CADRE_SECRET_TOKEN' },
+      { secretsDir: '', env }
+    );
+    const node: WorkflowNode = {
+      id: 't',
+      type: 'transform',
+      position: { x: 0, y: 0 },
+      data: { label: 't', template: 'Authorization: {{token}}' },
+    };
+    const state = await new Executor([node], [], { variables, secrets }).execute();
+
+    expect(state.context.node_t_output).toBe('Authorization: [REDACTED]');
+    expect(JSON.stringify(state)).not.toContain('s3cr3t');
+  });
+
+  it('masks secrets in retry and failure messages', async () => {
+    const { variables, secrets } = resolveSecretVariables(
+      { token: 'secret://env/CADRE_SECRET_TOKEN' },
+      { secretsDir: '', env }
+    );
+    const node: WorkflowNode = {
+      id: 'a',
+      type: 'agent',
+      position: { x: 0, y: 0 },
+      data: { label: 'a', systemPrompt: 'use {{token}}', retries: 1, retryJitter: 'full' },
+    };
+    const events: unknown[] = [];
+    seen.fail = true;
+    try {
+      const state = await new Executor([node], [], {
+        variables,
+        secrets,
+        random: () => 0,
+        onEvent: (event) => { events.push(event); },
+      }).execute();
+
+      expect(state.nodeStates.a.error).toBe('CLI exited: could not use prompt "use [REDACTED]"');
+      expect(JSON.stringify(state)).not.toContain('s3cr3t');
+    } finally {
+      seen.fail = false;
+    }
+    expect(events.map(e => (e as { type: string }).type)).toEqual(expect.arrayContaining(['node-retry', 'node-error']));
+    expect(JSON.stringify(events)).not.toContain('s3cr3t');
+  });
+});
@@ -0,0 +1,99 @@
+import { describe, it, expect, vi, afterEach } from 'vitest';
+import { createSseWriter, parseEventTypeFilter } from '../sse';
+
+describe('parseEventTypeFilter', () => {
+  it('returns no filter when types is absent', () => {
+    expect(parseEventTypeFilter(new URLSearchParams())).toEqual({ types: null, unknown: [] });
+  });
+
+  it('accepts comma-separated and repeated params', () => {
+    const { types, unknown } = parseEventTypeFilter(new URLSearchParams('types=state,done&types=error'));
+    expect(unknown).toEqual([]);
+    expect([...types!].sort()).toEqual(['done', 'error', 'state']);
+    expect(types!.has('heartbeat')).toBe(false);
+  });
+
+  it('reports unknown types', () => {
+    const { unknown } = parseEventTypeFilter(new URLSearchParams('types=state, text ,task.started'));
+    expect(unknown).toEqual(['text', 'task.started']);
+  });
+});
+
+describe('createSseWriter', () => {
+  function controller() {
+    return { enqueue: vi.fn(), close: vi.fn() };
+  }
+
+  it('encodes events as SSE frames', () => {
+    const c = controller();
+    const writer = createSseWriter(c, null);
+
+    expect(writer.send('state', { status: 'running' })).toBe(true);
+    expect(new TextDecoder().decode(c.enqueue.mock.calls[0][0])).toBe('event: state\ndata: {"status":"running"}\n\n');
+  });
+
+  it('skips filtered events without closing', () => {
+    const c = controller();
+    const writer = createSseWriter(c, new Set(['done' as const]));
+
+    expect(writer.send('state', {})).toBe(true);
+    expect(c.enqueue).not.toHaveBeenCalled();
+    expect(writer.closed).toBe(false);
+  });
+
+  it('stops writing after the client disconnects', () => {
+    const c = controller();
+    c.enqueue.mockImplementation(() => { throw new TypeError('Invalid state: Controller is already closed'); });
+    const writer = createSseWriter(c, null);
+
+    expect(writer.send('heartbeat', {})).toBe(false);
+    expect(writer.closed).toBe(true);
+    expect(writer.send('state', {})).toBe(false);
+    expect(c.enqueue).toHaveBeenCalledTimes(1);
+  });
+
+  it('closes the controller once', () => {
+    const c = controller();
+    const writer = createSseWriter(c, null);
+
+    writer.close();
+    writer.close();
+
+    expect(c.close).toHaveBeenCalledTimes(1);
+  });
+
+  describe('keepalive', () => {
+    afterEach(() => {
+      vi.useRealTimers();
+    });
+
+    const frames = (c: ReturnType<typeof controller>) =>
+      c.enqueue.mock.calls.map(([chunk]) => new TextDecoder().decode(chunk));
+
+    it('writes a comment after an idle gap and restarts the timer on each event', () => {
+      vi.useFakeTimers();
+      const c = controller();
+      const writer = createSseWriter(c, null, 15_000);
+
+      vi.advanceTimersByTime(10_000);
+      writer.send('state', {});
+      vi.advanceTimersByTime(10_000);
+      expect(frames(c)).toEqual(['event: state\ndata: {}\n\n']);
+
+      vi.advanceTimersByTime(5_000);
+      expect(frames(c)[1]).toBe(': keepalive\n\n');
+      vi.advanceTimersByTime(15_000);
+      expect(frames(c)).toHaveLength(3);
+    });
+
+    it('stops once the writer is closed', () => {
+      vi.useFakeTimers();
+      const c = controller();
+      const writer = createSseWriter(c, null, 1_000);
+
+      writer.close();
+      vi.advanceTimersByTime(5_000);
+      expect(c.enqueue).not.toHaveBeenCalled();
+    });
+  });
+});
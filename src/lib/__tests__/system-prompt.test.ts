@@ -0,0 +1,63 @@
+import { describe, it, expect, vi, beforeEach } from 'vitest';
+import { Executor } from '../engine/executor';
+import type { WorkflowNode } from '../engine/types';
+import type { ProviderMessage } from '@/types/provider';
+
+const seen = vi.hoisted(() => ({ system: '' as string | undefined }));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'echo',
+    name: 'Echo',
+    async *stream(messages: ProviderMessage[]) {
+      seen.system = messages.find(m => m.role === 'system')?.content;
+      yield { type: 'text' as const, content: 'ok' };
+    },
+  }),
+}));
+
+function agent(systemPrompt: string): WorkflowNode {
+  return { id: 'a', type: 'agent', position: { x: 0, y: 0 }, data: { label: 'a', systemPrompt } };
+}
+
+describe('system prompt templating', () => {
+  beforeEach(() => {
+    seen.system = undefined;
+  });
+
+  it('renders workflow name, date and variables', async () => {
+    await new Executor([agent('You work on {{workflow}} for {{team}} on {{date}}.')], [], {
+      workflowName: 'Release notes',
+      variables: { team: 'platform' },
+    }).execute();
+
+    expect(seen.system).toMatch(/^You work on Release notes for platform on \d{4}-\d{2}-\d{2}\.$/);
+  });
+
+  it('renders unknown names as empty', async () => {
+    await new Executor([agent('Hello {{missing}}!')], [], {}).execute();
+
+    expect(seen.system).toBe('Hello !');
+  });
+
+  it('lets variables shadow built-ins', async () => {
+    await new Executor([agent('{{date}}')], [], { variables: { date: 'yesterday' } }).execute();
+
+    expect(seen.system).toBe('yesterday');
+  });
+
+  it('wraps the node prompt in the configured prefix and suffix', async () => {
+    await new Executor([agent('Review the diff.')], [], {
+      systemPromptPrefix: 'Never reveal secrets.',
+      systemPromptSuffix: 'Answer in English.',
+    }).execute();
+
+    expect(seen.system).toBe('Never reveal secrets.\n\nReview the diff.\n\nAnswer in English.');
+  });
+
+  it('sends the prefix alone when the node has no prompt', async () => {
+    await new Executor([agent('')], [], { systemPromptPrefix: 'Never reveal secrets.' }).execute();
+
+    expect(seen.system).toBe('Never reveal secrets.');
+  });
+});
@@ -0,0 +1,110 @@
+import { describe, it, expect, vi, beforeEach, afterEach } from 'vitest';
+import { acquireRateLimit, resetRateLimits, TokenBucket } from '../providers/throttle';
+import { Executor } from '../engine/executor';
+import type { WorkflowNode } from '../engine/types';
+
+const calls = vi.hoisted(() => ({ at: [] as number[] }));
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'counting',
+    name: 'Counting',
+    async *stream() {
+      calls.at.push(Date.now());
+      yield { type: 'text' as const, content: 'ok' };
+    },
+  }),
+}));
+
+function agent(id: string): WorkflowNode {
+  return { id, type: 'agent', position: { x: 0, y: 0 }, data: { label: id } };
+}
+
+// Whether the promise settles once pending microtasks have run
+async function settles(promise: Promise<unknown>): Promise<boolean> {
+  let settled = false;
+  promise.then(() => { settled = true; }, () => { settled = true; });
+  for (let i = 0; i < 10; i++) await Promise.resolve();
+  return settled;
+}
+
+describe('TokenBucket', () => {
+  beforeEach(() => {
+    vi.useFakeTimers();
+  });
+
+  afterEach(() => {
+    vi.useRealTimers();
+  });
+
+  it('lets the first call through and spaces the rest', async () => {
+    let now = 0;
+    const bucket = new TokenBucket(60, () => now);
+
+    expect(await settles(bucket.acquire())).toBe(true);
+    now = 1000;
+    expect(await settles(bucket.acquire())).toBe(true);
+
+    now = 1500;
+    const pending = bucket.acquire();
+    expect(await settles(pending)).toBe(false);
+    await vi.advanceTimersByTimeAsync(499);
+    expect(await settles(pending)).toBe(false);
+
+    now = 2000;
+    await vi.advanceTimersByTimeAsync(1);
+    expect(await settles(pending)).toBe(true);
+  });
+
+  it('stops waiting when aborted', async () => {
+    const bucket = new TokenBucket(1);
+    await bucket.acquire();
+
+    const controller = new AbortController();
+    const pending = bucket.acquire(controller.signal);
+    controller.abort();
+
+    await expect(pending).rejects.toThrow('Aborted while waiting for provider rate limit');
+  });
+});
+
+describe('provider rate limiting', () => {
+  beforeEach(() => {
+    calls.at = [];
+    resetRateLimits();
+  });
+
+  it('is a no-op without a limit', async () => {
+    for (let i = 0; i < 3; i++) {
+      expect(await settles(acquireRateLimit('provider:x', 0))).toBe(true);
+      expect(await settles(acquireRateLimit('provider:x', undefined))).toBe(true);
+    }
+  });
+
+  it('spaces concurrent provider calls by the configured rate', async () => {
+    const executor = new Executor([agent('a'), agent('b'), agent('c')], [], { providerRateLimit: 600 });
+
+    await executor.execute();
+
+    expect(calls.at).toHaveLength(3);
+    const sorted = [...calls.at].sort((x, y) => x - y);
+    for (let i = 1; i < sorted.length; i++) {
+      // 600/min is one call every 100ms; allow for timer slop
+      expect(sorted[i] - sorted[i - 1]).toBeGreaterThanOrEqual(95);
+    }
+  });
+
+  it('keeps node limits of different workflows apart', async () => {
+    const limited = { ...agent('agent-1'), data: { label: 'agent-1', rateLimit: 1 } };
+    const started = Date.now();
+
+    await Promise.all([
+      new Executor([limited], [], { workflowId: 'w1' }).execute(),
+      new Executor([limited], [], { workflowId: 'w2' }).execute(),
+    ]);
+
+    // A shared bucket would hold the second call for a minute
+    expect(calls.at).toHaveLength(2);
+    expect(Date.now() - started).toBeLessThan(1000);
+  });
+});
@@ -0,0 +1,64 @@
+import { describe, it, expect, vi } from 'vitest';
+import { truncateMiddle } from '../engine/truncate';
+import { Executor } from '../engine/executor';
+import type { ProviderMessage } from '@/types/provider';
+
+const prompts = vi.hoisted(() => [] as string[]);
+
+vi.mock('../providers/registry', () => ({
+  getProvider: () => ({
+    id: 'mock',
+    name: 'Mock',
+    async *stream(messages: ProviderMessage[]) {
+      prompts.push(messages[messages.length - 1].content);
+      yield { type: 'done' as const, content: '' };
+    },
+  }),
+}));
+
+describe('truncateMiddle', () => {
+  it('leaves short text alone', () => {
+    expect(truncateMiddle('hello', 10)).toEqual({ text: 'hello', truncatedBytes: 0 });
+  });
+
+  it('keeps head and tail around a marker', () => {
+    const result = truncateMiddle('a'.repeat(50) + 'b'.repeat(50), 20);
+    expect(result.truncatedBytes).toBe(80);
+    expect(result.text).toBe(`${'a'.repeat(10)}\n[...truncated 80 bytes...]\n${'b'.repeat(10)}`);
+  });
+
+  it('measures UTF-8 bytes, not characters', () => {
+    expect(truncateMiddle('é'.repeat(10), 20).truncatedBytes).toBe(0);
+    expect(truncateMiddle('é'.repeat(11), 20).truncatedBytes).toBe(2);
+  });
+
+  it('cuts on character boundaries', () => {
+    // Both cut points fall inside a 4-byte emoji
+    const result = truncateMiddle(`${'a'.repeat(9)}😀${'b'.repeat(20)}😀${'c'.repeat(8)}`, 20);
+    expect(result.text).toBe(`${'a'.repeat(9)}\n[...truncated 28 bytes...]\n${'c'.repeat(8)}`);
+    expect(result.text).not.toContain('\uFFFD');
+  });
+
+  it('is disabled by a zero limit', () => {
+    expect(truncateMiddle('x'.repeat(1000), 0).truncatedBytes).toBe(0);
+  });
+});
+
+describe('Executor upstream output cap', () => {
+  it('caps oversized upstream output in the agent prompt', async () => {
+    const executor = new Executor(
+      [
+        { id: 'big', type: 'transform', position: { x: 0, y: 0 }, data: { label: 'Big', template: 'x'.repeat(5000) } },
+        { id: 'agent', type: 'agent', position: { x: 0, y: 0 }, data: { label: 'Agent' } },
+      ],
+      [{ id: 'e1', source: 'big', target: 'agent' }],
+      { maxUpstreamBytes: 100 }
+    );
+
+    const result = await executor.execute();
+
+    expect(prompts[0]).toBe(`${'x'.repeat(50)}\n[...truncated 4900 bytes...]\n${'x'.repeat(50)}`);
+    // The stored output of the upstream node stays complete
+    expect(result.nodeStates.big.output).toHaveLength(5000);
+  });
+});
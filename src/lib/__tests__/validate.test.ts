@@ -0,0 +1,92 @@
+import { describe, it, expect } from 'vitest';
+import { findUnknownProviders, validateWorkflow, validateWorkflows } from '../engine/validate';
+import { registerProvider } from '../providers/registry';
+
+const node = (id: string, type = 'agent', data: Record<string, unknown> = {}) => ({
+  id,
+  type,
+  position: { x: 0, y: 0 },
+  data: { label: id, ...data },
+});
+
+describe('validateWorkflow', () => {
+  it('accepts a valid graph', () => {
+    const graph = {
+      nodes: [node('in', 'input'), node('a')],
+      edges: [{ id: 'e1', source: 'in', target: 'a' }],
+    };
+    expect(validateWorkflow(graph)).toEqual([]);
+  });
+
+  it('reports a broken edge reference', () => {
+    const graph = {
+      nodes: [node('a')],
+      edges: [{ id: 'e1', source: 'a', target: 'missing' }],
+    };
+    expect(validateWorkflow(graph)).toContain('Edge "e1" references unknown target node "missing"');
+  });
+
+  it('reports schema problems with their path', () => {
+    const errors = validateWorkflow({ nodes: [node('a', 'crew')], edges: [] });
+    expect(errors).toHaveLength(1);
+    expect(errors[0]).toMatch(/^nodes\.0\.type: /);
+  });
+
+  it('reports node configuration problems together', () => {
+    const graph = {
+      nodes: [node('r', 'router'), node('t', 'transform'), node('r', 'agent')],
+      edges: [],
+    };
+    expect(validateWorkflow(graph)).toEqual(expect.arrayContaining([
+      'Duplicate node id "r"',
+      'Router node "r" must have at least one route',
+      'Transform node "t" must have a template',
+    ]));
+  });
+});
+
+describe('validateWorkflows', () => {
+  it('keeps the source of each result', () => {
+    const results = validateWorkflows([
+      { name: 'ok', source: 'ok.json', graphData: { nodes: [node('a')], edges: [] } },
+      { name: 'bad', source: 'bad.json', graphData: { nodes: [], edges: [] } },
+    ]);
+    expect(results.map(r => [r.source, r.errors.length > 0])).toEqual([
+      ['ok.json', false],
+      ['bad.json', true],
+    ]);
+  });
+});
+
+describe('findUnknownProviders', () => {
+  it('accepts known providers and nodes without one', () => {
+    const graph = { nodes: [node('a', 'agent', { provider: 'codex' }), node('b'), node('t', 'transform')], edges: [] };
+    expect(findUnknownProviders(graph)).toEqual([]);
+  });
+
+  it('reports unknown providers by node label', () => {
+    const graph = { nodes: [node('a', 'agent', { provider: 'gpt-9' })], edges: [] };
+    expect(findUnknownProviders(graph)).toEqual(['Node "a" uses unknown provider "gpt-9"']);
+  });
+
+  it('accepts providers added with registerProvider', () => {
+    registerProvider({
+      id: 'in-house',
+      name: 'In-house',
+      async *stream() {
+        yield { type: 'done' as const, content: '' };
+      },
+      validateCli: async () => true,
+    });
+    const graph = {
+      nodes: [node('in', 'input'), node('a', 'agent', { provider: 'in-house' })],
+      edges: [{ id: 'e1', source: 'in', target: 'a' }],
+    };
+    expect(findUnknownProviders(graph)).toEqual([]);
+    expect(validateWorkflow(graph)).toEqual([]);
+  });
+
+  it('ignores missing graph data', () => {
+    expect(findUnknownProviders(undefined)).toEqual([]);
+  });
+});
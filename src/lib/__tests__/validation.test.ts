@@ -6,6 +6,7 @@ import {
   updateWorkflowSchema,
   listRunsQuerySchema,
   cancelRunSchema,
+  startRunSchema,
   parseBody,
   parseQuery,
   parseUuid,
@@ -61,6 +62,12 @@ describe('createWorkflowSchema', () => {
     expect(result.variables).toEqual({});
   });
 
+  it('accepts a run timeout within bounds', () => {
+    expect(createWorkflowSchema.parse({ name: 'w', timeout: 600 }).timeout).toBe(600);
+    expect(createWorkflowSchema.safeParse({ name: 'w', timeout: 5 }).success).toBe(false);
+    expect(createWorkflowSchema.safeParse({ name: 'w', timeout: 90000 }).success).toBe(false);
+  });
+
   it('trims name', () => {
     const result = createWorkflowSchema.parse({ name: '  spaced  ' });
     expect(result.name).toBe('spaced');
@@ -133,6 +140,43 @@ describe('listRunsQuerySchema', () => {
     expect(result.limit).toBe(50);
     expect(result.offset).toBe(0);
   });
+
+  it('splits a label filter on the first =', () => {
+    const result = listRunsQuerySchema.parse({ label: 'project=alpha=1' });
+    expect(result.label).toEqual({ key: 'project', value: 'alpha=1' });
+  });
+
+  it('rejects a label filter without a value separator', () => {
+    expect(listRunsQuerySchema.safeParse({ label: 'project' }).success).toBe(false);
+  });
+});
+
+describe('startRunSchema', () => {
+  it('accepts labels', () => {
+    const result = startRunSchema.parse({ labels: { project: 'alpha', env: 'dev' } });
+    expect(result.labels).toEqual({ project: 'alpha', env: 'dev' });
+  });
+
+  it('rejects invalid label keys', () => {
+    expect(startRunSchema.safeParse({ labels: { 'bad key': 'x' } }).success).toBe(false);
+  });
+
+  it('accepts run overrides', () => {
+    const result = startRunSchema.parse({ overrides: { provider: 'codex', model: 'gpt-5-codex', maxTurns: 5 } });
+    expect(result.overrides).toEqual({ provider: 'codex', model: 'gpt-5-codex', maxTurns: 5 });
+  });
+
+  it('rejects unknown override providers and fields', () => {
+    expect(startRunSchema.safeParse({ overrides: { provider: 'nope' } }).success).toBe(false);
+    expect(startRunSchema.safeParse({ overrides: { model: '--yolo' } }).success).toBe(false);
+    // The provider CLIs take no sampling settings
+    expect(startRunSchema.safeParse({ overrides: { temperature: 0.2 } }).success).toBe(false);
+  });
+
+  it('rejects more than 20 labels', () => {
+    const labels = Object.fromEntries(Array.from({ length: 21 }, (_, i) => [`k${i}`, 'v']));
+    expect(startRunSchema.safeParse({ labels }).success).toBe(false);
+  });
 });
 
 describe('cancelRunSchema', () => {
@@ -0,0 +1,40 @@
+import { describe, it, expect } from 'vitest';
+import { checkImportItems, findNameConflicts } from '../workflow-import';
+
+const agent = (id: string) => ({ id, type: 'agent', position: { x: 0, y: 0 }, data: { label: id } });
+
+describe('checkImportItems', () => {
+  it('reports partial success with itemized errors', () => {
+    const { valid, results } = checkImportItems([
+      { name: 'ok', graphData: { nodes: [agent('a'), agent('b')], edges: [{ id: 'e', source: 'a', target: 'b' }] } },
+      { description: 'no name' },
+      { name: 'bad-graph', graphData: {
+        nodes: [{ id: 't', type: 'transform', position: { x: 0, y: 0 }, data: { label: 't' } }],
+        edges: [],
+      } },
+      { name: 'empty' },
+    ]);
+
+    expect(valid.map(v => v.index)).toEqual([0, 3]);
+    expect(results).toHaveLength(4);
+    expect(results[0].errors).toBeUndefined();
+    expect(results[1].errors?.[0]).toMatch(/^name:/);
+    expect(results[2]).toEqual({ index: 2, name: 'bad-graph', errors: ['Transform node "t" must have a template'] });
+  });
+
+  it('keeps the name of items that fail schema validation', () => {
+    const { results } = checkImportItems([{ name: 'x', description: 1 }]);
+    expect(results[0].name).toBe('x');
+    expect(results[0].errors?.[0]).toMatch(/^description:/);
+  });
+});
+
+describe('findNameConflicts', () => {
+  it('lists names that already exist or repeat within the import', () => {
+    expect(findNameConflicts(['b', 'a', 'c', 'c'], ['a', 'z'])).toEqual(['a', 'c']);
+  });
+
+  it('returns nothing when all names are free', () => {
+    expect(findNameConflicts(['a', 'b'], [])).toEqual([]);
+  });
+});
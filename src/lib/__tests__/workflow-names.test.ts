@@ -0,0 +1,18 @@
+import { describe, it, expect } from 'vitest';
+import { copyNamePattern, nextCopyName } from '../workflow-names';
+
+describe('nextCopyName', () => {
+  it('uses "(Copy)" when free', () => {
+    expect(nextCopyName('Triage', ['Triage'])).toBe('Triage (Copy)');
+  });
+
+  it('numbers further copies', () => {
+    expect(nextCopyName('Triage', ['Triage (Copy)', 'Triage (Copy 2)'])).toBe('Triage (Copy 3)');
+  });
+});
+
+describe('copyNamePattern', () => {
+  it('escapes LIKE wildcards in the source name', () => {
+    expect(copyNamePattern('100%_done')).toBe('100\\%\\_done (Copy%');
+  });
+});
@@ -0,0 +1,65 @@
+import { describe, it, expect } from 'vitest';
+import {
+  NODE_TYPES,
+  SCHEMA_KINDS,
+  getJsonSchema,
+  isSchemaKind,
+  workflowNodeSchema,
+} from '../workflow-schema';
+import { listProviders, registerProvider } from '../providers/registry';
+
+type JsonSchema = {
+  properties: Record<string, JsonSchema>;
+  enum?: string[];
+};
+
+describe('getJsonSchema', () => {
+  it('lists the valid node type enum values', () => {
+    const schema = getJsonSchema('node') as unknown as JsonSchema;
+    expect(schema.properties.type.enum).toEqual([...NODE_TYPES]);
+  });
+
+  it('lists registered providers on node data', () => {
+    registerProvider({ id: 'schema-test', name: 'Schema Test', stream: async function* () {}, validateCli: async () => true });
+
+    const schema = getJsonSchema('node') as unknown as JsonSchema;
+    const ids = schema.properties.data.properties.provider.enum;
+    expect(ids).toEqual(listProviders().map(p => p.id));
+    expect(ids).toEqual(expect.arrayContaining(['claude-code', 'codex', 'gemini', 'bedrock', 'schema-test']));
+  });
+
+  it('exports every kind', () => {
+    for (const kind of SCHEMA_KINDS) {
+      expect(getJsonSchema(kind).title).toBe(`cadre ${kind}`);
+    }
+  });
+});
+
+describe('isSchemaKind', () => {
+  it('accepts known kinds only', () => {
+    expect(isSchemaKind('workflow')).toBe(true);
+    expect(isSchemaKind('crew')).toBe(false);
+  });
+});
+
+describe('workflowNodeSchema', () => {
+  it('accepts a node with extra data fields', () => {
+    const result = workflowNodeSchema.safeParse({
+      id: 'a',
+      type: 'loop',
+      position: { x: 0, y: 0 },
+      data: { label: 'Loop', maxIterations: 3 },
+    });
+    expect(result.success).toBe(true);
+  });
+
+  it('rejects an unknown node type', () => {
+    const result = workflowNodeSchema.safeParse({
+      id: 'a',
+      type: 'crew',
+      position: { x: 0, y: 0 },
+      data: { label: 'A' },
+    });
+    expect(result.success).toBe(false);
+  });
+});
@@ -14,12 +14,45 @@ interface AuthConfig {
 interface AppConfig {
   env: CadreEnv;
   logLevel: string;
+  shutdownTimeoutMs: number;
+  // Idle time before a run stream writes a keepalive comment; 0 disables
+  sseKeepaliveMs: number;
+}
+
+interface EngineConfig {
+  maxUpstreamBytes: number;
+  // Parallel nodes run at once per run; 0 uses the engine default
+  concurrency: number;
+  // Runs executing at once on this server; 0 is unlimited
+  maxConcurrentRuns: number;
+  // Runs held waiting for a slot once at capacity; 0 rejects them instead
+  runQueueSize: number;
+  // Requests per minute to each provider, shared by all runs; 0 is unlimited
+  providerRateLimit: number;
+  responseCacheTtlMs: number;
+  // Default whole-run timeout in seconds for workflows without one; 0 disables
+  runTimeoutSeconds: number;
+  // Added before and after every agent's system prompt
+  systemPromptPrefix: string;
+  systemPromptSuffix: string;
+  // Per-run output directory, relative to the workflow workspace; empty saves to the workspace root
+  artifactsDir: string;
+  // Directory secret://file/ variable references resolve within; empty disables them
+  secretsDir: string;
+  // AWS region for the bedrock provider
+  bedrockRegion: string;
+  // Log provider requests and responses at debug level, with credentials redacted
+  providerDebug: boolean;
+  // Record/replay provider traffic to this JSONL file when set
+  replayPath: string;
+  replayMode: 'record' | 'replay';
 }
 
 interface Config {
   db: DbConfig;
   auth: AuthConfig;
   app: AppConfig;
+  engine: EngineConfig;
 }
 
 let _config: Config | null = null;
@@ -58,6 +91,25 @@ export function getConfig(): Config {
     app: {
       env,
       logLevel: optionalVar('LOG_LEVEL', env === 'prod' ? 'warn' : 'debug'),
+      shutdownTimeoutMs: parseInt(optionalVar('SHUTDOWN_TIMEOUT_MS', '10000'), 10),
+      sseKeepaliveMs: parseInt(optionalVar('SSE_KEEPALIVE_MS', '15000'), 10),
+    },
+    engine: {
+      maxUpstreamBytes: parseInt(optionalVar('MAX_UPSTREAM_BYTES', '100000'), 10),
+      concurrency: parseInt(optionalVar('NODE_CONCURRENCY', '4'), 10),
+      maxConcurrentRuns: parseInt(optionalVar('MAX_CONCURRENT_RUNS', '10'), 10),
+      runQueueSize: parseInt(optionalVar('RUN_QUEUE_SIZE', '0'), 10),
+      providerRateLimit: parseInt(optionalVar('PROVIDER_RATE_LIMIT', '0'), 10),
+      responseCacheTtlMs: parseInt(optionalVar('RESPONSE_CACHE_TTL_MS', '3600000'), 10),
+      runTimeoutSeconds: parseInt(optionalVar('RUN_TIMEOUT_SECONDS', '0'), 10),
+      secretsDir: optionalVar('SECRETS_DIR', ''),
+      systemPromptPrefix: optionalVar('SYSTEM_PROMPT_PREFIX', ''),
+      systemPromptSuffix: optionalVar('SYSTEM_PROMPT_SUFFIX', ''),
+      bedrockRegion: optionalVar('AWS_REGION', 'us-east-1'),
+      artifactsDir: optionalVar('ARTIFACTS_DIR', ''),
+      providerDebug: optionalVar('PROVIDER_DEBUG', 'false') === 'true',
+      replayPath: optionalVar('CADRE_REPLAY', ''),
+      replayMode: optionalVar('CADRE_REPLAY_MODE', 'replay') === 'record' ? 'record' : 'replay',
     },
   };
 
@@ -4,7 +4,7 @@
  * other apps sharing this database.
  */
 
-import { pgSchema, text, timestamp, jsonb, uuid, index } from 'drizzle-orm/pg-core';
+import { pgSchema, text, timestamp, jsonb, uuid, index, integer, uniqueIndex } from 'drizzle-orm/pg-core';
 import { users } from './shared';
 
 export const cadreSchema = pgSchema('cadre');
@@ -16,6 +16,8 @@ export const workflows = cadreSchema.table('workflows', {
   description: text('description').default(''),
   graphData: jsonb('graph_data').notNull().default({ nodes: [], edges: [] }),
   variables: jsonb('variables').default({}),
+  // Whole-run ceiling in seconds; falls back to RUN_TIMEOUT_SECONDS when null
+  timeout: integer('timeout'),
   createdAt: timestamp('created_at').defaultNow().notNull(),
   updatedAt: timestamp('updated_at').defaultNow().notNull(),
 }, (table) => [
@@ -31,6 +33,14 @@ export const runs = cadreSchema.table('runs', {
   context: jsonb('context').default({}),
   nodeStates: jsonb('node_states').default({}),
   tokenUsage: jsonb('token_usage').default({ input: 0, output: 0, cost: 0 }),
+  labels: jsonb('labels').default({}),
+  // Start-time overrides applied to every agent node, kept for traceability
+  overrides: jsonb('overrides').default({}),
+  // Start-time inputs, kept so queued runs can be started later
+  inputs: jsonb('inputs').default({}),
+  // Dispatch order while queued; higher goes first
+  priority: integer('priority').notNull().default(0),
+  // Queue time for queued runs, start time once running
   startedAt: timestamp('started_at').defaultNow().notNull(),
   completedAt: timestamp('completed_at'),
 }, (table) => [
@@ -39,4 +49,18 @@ export const runs = cadreSchema.table('runs', {
   index('idx_runs_status').on(table.status),
   index('idx_runs_started_at').on(table.startedAt),
   index('idx_runs_user_workflow').on(table.userId, table.workflowId),
+  index('idx_runs_labels').using('gin', table.labels),
+]);
+
+// Lifecycle events of a run in emission order, for replaying its timeline
+export const runEvents = cadreSchema.table('run_events', {
+  id: uuid('id').primaryKey().defaultRandom(),
+  runId: uuid('run_id').notNull().references(() => runs.id, { onDelete: 'cascade' }),
+  seq: integer('seq').notNull(),
+  type: text('type').notNull(),
+  nodeId: text('node_id'),
+  data: jsonb('data').default({}),
+  createdAt: timestamp('created_at').defaultNow().notNull(),
+}, (table) => [
+  uniqueIndex('idx_run_events_run_seq').on(table.runId, table.seq),
 ]);
@@ -12,4 +12,5 @@ export {
   cadreSchema,
   workflows,
   runs,
+  runEvents,
 } from './cadre';
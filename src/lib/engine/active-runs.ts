@@ -0,0 +1,94 @@
+import type { Executor } from './executor';
+
+export interface ActiveRun {
+  runId: string;
+  executor: Executor;
+  done: Promise<unknown>;
+}
+
+const activeRuns = new Map<string, ActiveRun>();
+let draining = false;
+// Runs that claimed a slot but haven't registered yet
+let starting = 0;
+
+export class RunCapacityError extends Error {
+  constructor(readonly limit: number) {
+    super(`Too many concurrent runs (limit ${limit})`);
+    this.name = 'RunCapacityError';
+  }
+}
+
+/** Tracks a run until `done` settles; resolves once it is unregistered and its slot is free. */
+export function registerActiveRun(runId: string, executor: Executor, done: Promise<unknown>): Promise<void> {
+  const entry: ActiveRun = { runId, executor, done };
+  activeRuns.set(runId, entry);
+  return done
+    .catch(() => { /* failures are recorded by the caller */ })
+    .finally(() => {
+      if (activeRuns.get(runId) === entry) activeRuns.delete(runId);
+    });
+}
+
+export function getActiveRun(runId: string): ActiveRun | undefined {
+  return activeRuns.get(runId);
+}
+
+export function listActiveRuns(): ActiveRun[] {
+  return [...activeRuns.values()];
+}
+
+/**
+ * Reserves a slot for a run that is about to start, counting runs still being
+ * set up so a burst of requests can't overshoot `limit` (0 = unlimited).
+ * Throws RunCapacityError when full; call the returned release once the run
+ * is registered or failed to start.
+ */
+export function claimRunSlot(limit: number): () => void {
+  if (limit > 0 && activeRuns.size + starting >= limit) {
+    throw new RunCapacityError(limit);
+  }
+  starting++;
+  let released = false;
+  return () => {
+    if (released) return;
+    released = true;
+    starting--;
+  };
+}
+
+export function isDraining(): boolean {
+  return draining;
+}
+
+/**
+ * Stops accepting new runs, aborts every active executor and waits up to
+ * `timeoutMs` for them to settle. Returns the runs still in flight at the
+ * deadline so the caller can checkpoint them.
+ */
+export async function drainActiveRuns(timeoutMs: number): Promise<ActiveRun[]> {
+  draining = true;
+
+  const pending = listActiveRuns();
+  for (const run of pending) {
+    run.executor.abort();
+  }
+
+  const settled = new Set<string>();
+  let timer: ReturnType<typeof setTimeout> | undefined;
+  await Promise.race([
+    Promise.all(pending.map(run =>
+      run.done.then(() => settled.add(run.runId), () => settled.add(run.runId))
+    )),
+    new Promise<void>(resolve => { timer = setTimeout(resolve, timeoutMs); }),
+  ]);
+  clearTimeout(timer);
+
+  return pending.filter(run => !settled.has(run.runId));
+}
+
+// Test helper
+export function resetActiveRuns(): void {
+  activeRuns.clear();
+  draining = false;
+  starting = 0;
+}
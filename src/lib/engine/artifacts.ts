@@ -0,0 +1,26 @@
+import type { NodeRunState } from './types';
+
+export interface Artifact {
+  nodeId: string;
+  path: string;
+  size: number;
+}
+
+/**
+ * Flattens the files each node created or changed into one list, relative to
+ * the workspace. When several nodes touched the same file, the last one wins.
+ */
+export function collectArtifacts(nodeStates: Record<string, NodeRunState>): Artifact[] {
+  const byPath = new Map<string, Artifact>();
+
+  const ordered = Object.entries(nodeStates).sort(([, a], [, b]) =>
+    new Date(a.completedAt ?? 0).getTime() - new Date(b.completedAt ?? 0).getTime()
+  );
+  for (const [nodeId, state] of ordered) {
+    for (const file of state.files ?? []) {
+      byPath.set(file.path, { nodeId, path: file.path, size: file.size });
+    }
+  }
+
+  return [...byPath.values()].sort((a, b) => a.path.localeCompare(b.path));
+}
@@ -0,0 +1,114 @@
+import type { CodingAgentProvider } from '../providers/base';
+import type { ProviderMessage, ProviderOptions } from '@/types/provider';
+
+// Approximate context windows (tokens) for the model behind each provider CLI
+export const CONTEXT_WINDOWS: Record<string, number> = {
+  'claude-code': 200_000,
+  codex: 272_000,
+  gemini: 1_000_000,
+  bedrock: 200_000,
+};
+
+const DEFAULT_CONTEXT_WINDOW = 200_000;
+
+// Share of the window left free for the agent's own turns and response
+const RESPONSE_RESERVE = 0.2;
+
+// The prompt can't fit however often it is retried, so the executor fails the node at once
+export class ContextWindowError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'ContextWindowError';
+  }
+}
+
+export interface PromptSection {
+  label: string;
+  text: string;
+}
+
+export interface FittedPrompt {
+  sections: PromptSection[];
+  dropped: string[];
+}
+
+/**
+ * Rough token estimate (~4 characters per token). CLI providers don't expose
+ * a tokenizer, so this errs on the side of dropping context early.
+ */
+export function estimateTokens(text: string): number {
+  return Math.ceil(text.length / 4);
+}
+
+/**
+ * Token count for a provider request. Uses the provider's own counter when it
+ * has one and falls back to the character heuristic otherwise, or when the
+ * counter fails.
+ */
+export async function countPromptTokens(
+  provider: CodingAgentProvider,
+  messages: ProviderMessage[],
+  options: ProviderOptions = {}
+): Promise<number> {
+  if (provider.countTokens) {
+    try {
+      return await provider.countTokens(messages, options);
+    } catch {
+      // fall through to the estimate
+    }
+  }
+  return messages.reduce((sum, m) => sum + estimateTokens(m.content), 0);
+}
+
+/**
+ * Counts each text once with countPromptTokens and returns a lookup suitable
+ * for fitToContextWindow.
+ */
+export async function promptTokenCounter(
+  provider: CodingAgentProvider,
+  texts: string[],
+  options: ProviderOptions = {}
+): Promise<(text: string) => number> {
+  const counts = new Map<string, number>();
+  await Promise.all([...new Set(texts)].map(async (text) => {
+    counts.set(text, await countPromptTokens(provider, [{ role: 'user', content: text }], options));
+  }));
+  return (text) => counts.get(text) ?? estimateTokens(text);
+}
+
+export function contextWindowFor(providerId: string, override?: number): number {
+  return override || CONTEXT_WINDOWS[providerId] || DEFAULT_CONTEXT_WINDOW;
+}
+
+/**
+ * Drops the oldest prompt sections until system prompt plus sections fit the
+ * window minus the response reserve. Throws a ContextWindowError when even
+ * the newest section alone does not fit. `count` defaults to the character
+ * estimate.
+ */
+export function fitToContextWindow(
+  system: string,
+  sections: PromptSection[],
+  window: number,
+  count: (text: string) => number = estimateTokens
+): FittedPrompt {
+  const budget = Math.floor(window * (1 - RESPONSE_RESERVE));
+  const systemTokens = count(system);
+  const kept = [...sections];
+  const dropped: string[] = [];
+
+  const total = () => systemTokens + kept.reduce((sum, s) => sum + count(s.text), 0);
+
+  while (kept.length > 1 && total() > budget) {
+    dropped.push(kept.shift()!.label);
+  }
+
+  if (total() > budget) {
+    const what = kept.length ? `input from "${kept[0].label}"` : 'system prompt';
+    throw new ContextWindowError(
+      `Prompt exceeds the context window: ${what} is ~${total()} tokens, limit is ${budget} (${window} minus response reserve)`
+    );
+  }
+
+  return { sections: kept, dropped };
+}
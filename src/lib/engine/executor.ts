@@ -2,22 +2,104 @@ import { Graph } from './graph';
 import { Scheduler } from './scheduler';
 import { RunContext } from './context';
 import { getProvider } from '../providers/registry';
-import type { WorkflowNode, RunState, ExecutionEvent } from './types';
-import { readdirSync, statSync, writeFileSync } from 'fs';
+import { isRetryableError } from '../providers/errors';
+import { acquireRateLimit } from '../providers/throttle';
+import type { CodingAgentProvider } from '../providers/base';
+import { computeBackoff, DEFAULT_RETRY_POLICY } from './retry';
+import { inputVariableName, resolveInputs } from './inputs';
+import { ContextWindowError, contextWindowFor, fitToContextWindow, promptTokenCounter, type PromptSection } from './context-window';
+import { truncateMiddle } from './truncate';
+import { DEFAULT_RESPONSE_CACHE_TTL_MS, getCachedResponse, responseCacheKey, setCachedResponse } from './response-cache';
+import { logger, type Logger } from '@/lib/logger';
+import { maskSecretValues } from '@/lib/secrets';
+import { buildOutputSchemaInstructions, buildCorrectionPrompt, validateOutput, PartialOutputTracker } from './output-schema';
+import type { WorkflowNode, RunState, ExecutionEvent, RunOverrides } from './types';
+import type { ProviderMessage, ProviderOptions } from '@/types/provider';
+import { mkdirSync, readdirSync, statSync, writeFileSync } from 'fs';
 import { join, relative } from 'path';
 
 export interface ExecutorOptions {
   variables?: Record<string, string>;
+  // Resolved secret variables; interpolated like variables but never stored in the run context
+  secrets?: Record<string, string>;
   workspacePath?: string;
+  // Where agent outputs are saved as <label>.md; defaults to the workspace root
+  artifactsPath?: string;
+  // Tags log lines from this run so concurrent runs can be told apart
+  runId?: string;
+  // Scopes per-node rate limits, which are shared by concurrent runs of the same workflow
+  workflowId?: string;
+  // Exposed to templates and system prompts as {{workflow}}
+  workflowName?: string;
+  // Wrapped around every agent's system prompt, e.g. organization-wide guardrails
+  systemPromptPrefix?: string;
+  systemPromptSuffix?: string;
+  overrides?: RunOverrides;
+  // Ceiling for the whole run in seconds; in-flight nodes are aborted when it passes
+  timeout?: number;
+  // Max nodes run at once within a parallel batch; DEFAULT_CONCURRENCY when unset or 0
+  concurrency?: number;
+  // Jitter source for retry backoff, overridable for deterministic tests
+  random?: () => number;
+  // Cap on each upstream output fed into an agent prompt; 0 disables
+  maxUpstreamBytes?: number;
+  // Requests per minute allowed to each provider across the run; 0 disables
+  providerRateLimit?: number;
+  // How long agent responses are reused for nodes with cacheResponses set
+  responseCacheTtlMs?: number;
   onEvent?: (event: ExecutionEvent) => void;
 }
 
+const DEFAULT_MAX_UPSTREAM_BYTES = 100_000;
+
+export const DEFAULT_CONCURRENCY = 4;
+// Each running node is a CLI process, so keep a hard ceiling regardless of config
+export const MAX_CONCURRENCY = 32;
+
+export function resolveConcurrency(value?: number): number {
+  if (value === undefined || value === 0) return DEFAULT_CONCURRENCY;
+  if (!Number.isInteger(value) || value < 0) {
+    throw new Error(`Invalid concurrency ${value}: must be a non-negative integer`);
+  }
+  return Math.min(value, MAX_CONCURRENCY);
+}
+
+interface AgentCapture {
+  text: string;
+  tokens: { input: number; output: number };
+}
+
 export class Executor {
   private graph: Graph;
   private scheduler: Scheduler;
   private context: RunContext;
   private workspacePath?: string;
+  private artifactsPath?: string;
+  private log: Logger;
+  private workflowName?: string;
+  private overrides: RunOverrides;
+  private timeout?: number;
+  private timedOut = false;
+  private runAbort = new AbortController();
+  private concurrency: number;
+  private random: () => number;
+  private maxUpstreamBytes: number;
+  private responseCacheTtlMs: number;
+  private providerRateLimit: number;
+  private secrets: Record<string, string>;
+  private secretValues: string[];
+  // Keeps node rate-limit buckets apart from other workflows that reuse node ids
+  private rateLimitScope: string;
+  private systemPromptPrefix?: string;
+  private systemPromptSuffix?: string;
+  // Nodes whose current attempt has streamed output
+  private outputStarted = new Set<string>();
+  // Abort handles of in-flight node attempts, for cancelling a single node
+  private nodeAborts = new Map<string, AbortController>();
+  private cancelledNodes = new Set<string>();
   private aborted = false;
+  private paused = false;
+  private resumeWaiters: (() => void)[] = [];
 
   constructor(
     nodes: WorkflowNode[],
@@ -28,6 +110,21 @@ export class Executor {
     this.scheduler = new Scheduler(this.graph);
     this.context = new RunContext(options.variables || {});
     this.workspacePath = options.workspacePath;
+    this.artifactsPath = options.artifactsPath;
+    this.log = options.runId ? logger.child({ runId: options.runId }) : logger;
+    this.workflowName = options.workflowName;
+    this.overrides = options.overrides || {};
+    this.timeout = options.timeout;
+    this.concurrency = resolveConcurrency(options.concurrency);
+    this.random = options.random || Math.random;
+    this.maxUpstreamBytes = options.maxUpstreamBytes ?? DEFAULT_MAX_UPSTREAM_BYTES;
+    this.responseCacheTtlMs = options.responseCacheTtlMs ?? DEFAULT_RESPONSE_CACHE_TTL_MS;
+    this.providerRateLimit = options.providerRateLimit ?? 0;
+    this.secrets = options.secrets || {};
+    this.secretValues = Object.values(this.secrets).filter(Boolean);
+    this.rateLimitScope = options.workflowId ?? crypto.randomUUID();
+    this.systemPromptPrefix = options.systemPromptPrefix;
+    this.systemPromptSuffix = options.systemPromptSuffix;
 
     if (options.onEvent) {
       this.context.onEvent(options.onEvent);
@@ -40,20 +137,39 @@ export class Executor {
       throw new Error(`Invalid workflow: ${validation.errors.join(', ')}`);
     }
 
+    const inputs = resolveInputs(this.graph.nodes, this.context.getAll());
+    if (inputs.errors.length > 0) {
+      throw new Error(`Invalid inputs: ${inputs.errors.join(', ')}`);
+    }
+    for (const [name, value] of Object.entries(inputs.values)) {
+      this.context.set(name, value);
+    }
+
     const startTime = new Date();
     let totalInputTokens = 0;
     let totalOutputTokens = 0;
 
+    const deadline = this.timeout
+      ? setTimeout(() => {
+          this.timedOut = true;
+          this.context.set('error', `Run timed out after ${this.timeout}s`);
+          this.abort();
+        }, this.timeout * 1000)
+      : undefined;
+
     while (!this.scheduler.isComplete() && !this.aborted) {
+      await this.waitWhilePaused();
+      if (this.aborted) break;
+
       const batch = this.scheduler.getNextBatch(this.context);
       if (!batch) break;
 
       if (batch.isParallel) {
-        await Promise.all(
-          batch.nodeIds.map(nodeId => this.executeNode(nodeId))
-        );
+        await this.executeParallel(batch.nodeIds);
       } else {
         for (const nodeId of batch.nodeIds) {
+          await this.waitWhilePaused();
+          if (this.aborted) break;
           await this.executeNode(nodeId);
         }
       }
@@ -67,8 +183,11 @@ export class Executor {
         this.scheduler.markCompleted(nodeId);
       }
     }
+    clearTimeout(deadline);
 
-    const status = this.aborted
+    const status = this.timedOut
+      ? 'failed' as const
+      : this.aborted
       ? 'cancelled' as const
       : Object.values(this.context.getAllNodeStates()).some(s => s.status === 'failed')
         ? 'failed' as const
@@ -100,8 +219,71 @@ export class Executor {
     return runState;
   }
 
+  // Stops dispatching and aborts in-flight nodes, which kills their CLI processes
   abort(): void {
     this.aborted = true;
+    this.runAbort.abort();
+    this.releaseWaiters();
+  }
+
+  /**
+   * Lets running nodes finish but holds back any node not yet dispatched
+   * until resume() is called.
+   */
+  pause(): void {
+    if (this.paused || this.aborted) return;
+    this.paused = true;
+    this.context.emit({ type: 'run-paused', data: {}, timestamp: new Date() });
+  }
+
+  resume(): void {
+    if (!this.paused) return;
+    this.paused = false;
+    this.releaseWaiters();
+    this.context.emit({ type: 'run-resumed', data: {}, timestamp: new Date() });
+  }
+
+  isPaused(): boolean {
+    return this.paused;
+  }
+
+  /**
+   * Fails one running node without retrying it, leaving the rest of the run
+   * going; its downstream nodes are skipped as for any failure. Returns
+   * false when the node has no attempt in flight.
+   */
+  cancelNode(nodeId: string): boolean {
+    const controller = this.nodeAborts.get(nodeId);
+    if (!controller) return false;
+    this.cancelledNodes.add(nodeId);
+    controller.abort();
+    return true;
+  }
+
+  private async waitWhilePaused(): Promise<void> {
+    while (this.paused && !this.aborted) {
+      await new Promise<void>(resolve => this.resumeWaiters.push(resolve));
+    }
+  }
+
+  private releaseWaiters(): void {
+    const waiters = this.resumeWaiters;
+    this.resumeWaiters = [];
+    for (const resolve of waiters) resolve();
+  }
+
+  // Dispatches nodes in scheduler order, keeping at most `concurrency` in flight
+  private async executeParallel(nodeIds: string[]): Promise<void> {
+    const queue = [...nodeIds];
+    const worker = async () => {
+      let nodeId: string | undefined;
+      while ((nodeId = queue.shift()) !== undefined) {
+        await this.waitWhilePaused();
+        if (this.aborted) return;
+        await this.executeNode(nodeId);
+      }
+    };
+    await Promise.all(Array.from({ length: Math.min(this.concurrency, queue.length) }, worker));
   }
 
   getState(): { nodeStates: Record<string, unknown>; totalTokens: { input: number; output: number; cost: number } } {
@@ -139,27 +321,63 @@ export class Executor {
       const timeoutMs = (node.data.timeout || 600) * 1000;
 
       while (retries >= 0) {
+        this.outputStarted.delete(nodeId);
         const abortController = new AbortController();
+        this.nodeAborts.set(nodeId, abortController);
         const timer = setTimeout(() => abortController.abort(), timeoutMs);
+        const onRunAbort = () => abortController.abort();
+        this.runAbort.signal.addEventListener('abort', onRunAbort, { once: true });
         try {
           await Promise.race([
             this.executeNodeByType(node, abortController.signal),
             new Promise<never>((_, reject) => {
-              abortController.signal.addEventListener('abort', () => {
-                reject(new Error(`Node "${node.data.label}" timed out after ${node.data.timeout || 600}s`));
-              });
+              const fail = () => reject(new Error(this.timedOut
+                ? `Run timed out after ${this.timeout}s`
+                : this.aborted
+                  ? 'Run was cancelled'
+                  : this.cancelledNodes.has(nodeId)
+                    ? `Node "${node.data.label}" was cancelled`
+                    : `Node "${node.data.label}" timed out after ${node.data.timeout || 600}s`));
+              if (abortController.signal.aborted) return fail();
+              abortController.signal.addEventListener('abort', fail);
             }),
           ]);
           lastError = null;
           break;
         } catch (error) {
           lastError = error as Error;
-          retries--;
+          const streamed = this.outputStarted.has(nodeId) && !node.data.retryAfterOutput;
+          if (streamed && retries > 0) {
+            this.log.warn('Not retrying after partial output', { nodeId, error: lastError.message });
+          }
+          const retryable = isRetryableError(error) && !(error instanceof ContextWindowError) && !this.timedOut && !this.aborted && !this.cancelledNodes.has(nodeId);
+          retries = retryable && !streamed ? retries - 1 : -1;
           if (retries >= 0) {
-            await new Promise(r => setTimeout(r, 1000 * Math.pow(2, (node.data.retries || 0) - retries - 1)));
+            const attempt = (node.data.retries || 0) - retries - 1;
+            const policy = { ...DEFAULT_RETRY_POLICY, jitter: node.data.retryJitter || DEFAULT_RETRY_POLICY.jitter };
+            const delayMs = computeBackoff(attempt, policy, this.random);
+            this.context.setNodeState(nodeId, { attempts: attempt + 2 });
+            this.context.emit({
+              type: 'node-retry',
+              nodeId,
+              data: { attempt: attempt + 2, error: maskSecretValues(lastError.message, this.secretValues), delayMs },
+              timestamp: new Date(),
+            });
+            // Cut the backoff short if the run is cancelled meanwhile
+            await new Promise<void>(resolve => {
+              const wake = () => { clearTimeout(backoff); resolve(); };
+              const backoff = setTimeout(() => {
+                this.runAbort.signal.removeEventListener('abort', wake);
+                resolve();
+              }, delayMs);
+              this.runAbort.signal.addEventListener('abort', wake, { once: true });
+            });
+            if (this.aborted) retries = -1;
           }
         } finally {
           clearTimeout(timer);
+          this.nodeAborts.delete(nodeId);
+          this.runAbort.signal.removeEventListener('abort', onRunAbort);
         }
       }
 
@@ -174,16 +392,35 @@ export class Executor {
         timestamp: new Date(),
       });
     } catch (error) {
-      const errorMessage = error instanceof Error ? error.message : String(error);
+      // Provider errors carry CLI stderr, which can echo the interpolated prompt
+      const errorMessage = maskSecretValues(error instanceof Error ? error.message : String(error), this.secretValues);
       this.context.setNodeState(nodeId, {
         status: 'failed',
         error: errorMessage,
         completedAt: new Date(),
       });
       this.context.emit({ type: 'node-error', nodeId, data: { error: errorMessage }, timestamp: new Date() });
+    } finally {
+      this.emitProgress();
     }
   }
 
+  /**
+   * Reports how many nodes have settled (completed, failed or skipped) so
+   * clients can show a progress bar without tracking node events themselves.
+   */
+  private emitProgress(): void {
+    const total = this.graph.nodes.length;
+    const completed = this.graph.nodes.filter(n =>
+      ['completed', 'failed', 'skipped'].includes(this.context.getNodeState(n.id).status)
+    ).length;
+    this.context.emit({
+      type: 'run-progress',
+      data: { completed, total, percent: total ? Math.round((completed / total) * 100) : 100 },
+      timestamp: new Date(),
+    });
+  }
+
   private async executeNodeByType(node: WorkflowNode, signal?: AbortSignal): Promise<void> {
     switch (node.type) {
       case 'agent':
@@ -199,7 +436,7 @@ export class Executor {
         await this.executeTransformNode(node);
         break;
       case 'gate':
-        await this.executeGateNode(node);
+        await this.executeGateNode(node, signal);
         break;
       case 'input':
         this.executeInputNode(node);
@@ -219,6 +456,7 @@ export class Executor {
   }
 
   private async executeAgentNode(node: WorkflowNode, signal?: AbortSignal): Promise<void> {
+    const log = this.log.child({ nodeId: node.id });
     const workspaceMode = node.data.workspace || 'off';
     const workspaceEnabled = workspaceMode !== 'off' && !!this.workspacePath;
 
@@ -229,64 +467,123 @@ export class Executor {
 
     // Build prompt from predecessor outputs
     const predecessors = this.graph.getPredecessors(node.id);
-    const previousOutputs = predecessors
-      .map(p => {
-        const output = this.context.getNodeOutput(p);
-        if (!output) return null;
+    const sections = predecessors
+      .map((p): PromptSection | null => {
+        const raw = this.context.getNodeOutput(p);
+        if (!raw) return null;
         const predNode = this.graph.getNode(p);
         const label = predNode?.data.label || p;
-        return predecessors.length > 1
-          ? `[Output from "${label}"]\n${output}`
-          : output;
+        const { text: output, truncatedBytes } = truncateMiddle(raw, this.maxUpstreamBytes);
+        if (truncatedBytes > 0) {
+          log.warn('Truncated upstream output', { from: p, truncatedBytes });
+        }
+        return {
+          label,
+          text: predecessors.length > 1 ? `[Output from "${label}"]\n${output}` : output,
+        };
       })
+      .filter((s): s is PromptSection => s !== null);
+
+    const schema = node.data.outputSchema;
+    const systemPrompt = [
+      this.systemPromptPrefix,
+      node.data.systemPrompt && this.interpolate(node.data.systemPrompt),
+      schema?.fields.length ? buildOutputSchemaInstructions(schema) : undefined,
+      this.systemPromptSuffix,
+    ]
       .filter(Boolean)
       .join('\n\n');
 
-    const systemPrompt = node.data.systemPrompt;
+    const providerId = this.overrides.provider || node.data.provider || 'claude-code';
+    const maxTurns = this.overrides.maxTurns ?? node.data.maxTurns;
+    const provider = getProvider(providerId);
+    const options: ProviderOptions = {
+      model: this.overrides.model,
+      workspacePath: workspaceEnabled ? this.workspacePath : undefined,
+      workspace: workspaceMode,
+      maxTurns,
+      signal,
+      secretValues: this.secretValues,
+    };
+
+    const count = await promptTokenCounter(provider, [systemPrompt, ...sections.map(s => s.text)], options);
+    const fitted = fitToContextWindow(systemPrompt, sections, contextWindowFor(providerId, node.data.contextWindow), count);
+    if (fitted.dropped.length > 0) {
+      log.warn('Dropped upstream outputs to fit context window', { dropped: fitted.dropped });
+    }
+    const previousOutputs = fitted.sections.map(s => s.text).join('\n\n');
 
-    const provider = getProvider(node.data.provider || 'claude-code');
+    const messages: ProviderMessage[] = [
+      ...(systemPrompt ? [{ role: 'system' as const, content: systemPrompt }] : []),
+      { role: 'user' as const, content: previousOutputs || (this.context.get('input') as string) || 'Begin' },
+    ];
 
+    // Workspace runs have side effects on disk, so only pure prompt/response nodes are cached
+    const cacheKey = node.data.cacheResponses && !workspaceEnabled
+      ? responseCacheKey(providerId, messages, maxTurns, options.model)
+      : null;
+    const cached = cacheKey ? getCachedResponse(cacheKey) : undefined;
+
+    const capture: AgentCapture = { text: '', tokens: { input: 0, output: 0 } };
     let fullOutput = '';
-    const stream = provider.stream(
-      [
-        ...(systemPrompt ? [{ role: 'system' as const, content: systemPrompt }] : []),
-        { role: 'user' as const, content: previousOutputs || (this.context.get('input') as string) || 'Begin' },
-      ],
-      {
-        model: 'claude-code',
-        workspacePath: workspaceEnabled ? this.workspacePath : undefined,
-        workspace: workspaceMode,
-        maxTurns: node.data.maxTurns,
-        signal,
-      },
-      ''
-    );
 
     try {
-      for await (const chunk of stream) {
-        if (chunk.type === 'text') {
-          fullOutput += chunk.content;
-          this.context.emit({
-            type: 'node-output',
-            nodeId: node.id,
-            data: { chunk: chunk.content },
-            timestamp: new Date(),
-          });
-        } else if (chunk.type === 'done' && chunk.tokens) {
-          this.context.setNodeState(node.id, { tokens: chunk.tokens });
+      if (cached !== undefined) {
+        log.info('Agent response cache hit');
+        capture.text = cached;
+        this.context.setNodeState(node.id, { cached: true });
+        this.context.emit({ type: 'node-output', nodeId: node.id, data: { chunk: cached }, timestamp: new Date() });
+      } else {
+        await this.streamAgent(node, provider, messages, options, capture);
+      }
+      fullOutput = capture.text;
+
+      if (schema?.strict && schema.fields.length > 0) {
+        let result = validateOutput(fullOutput, schema);
+        if (result.errors.length > 0) {
+          // Re-prompt once with the validation errors before failing the node
+          const previous = fullOutput;
+          capture.text = '';
+          await this.streamAgent(
+            node,
+            provider,
+            [...messages, { role: 'user', content: buildCorrectionPrompt(previous, result.errors) }],
+            options,
+            capture
+          );
+          fullOutput = capture.text;
+          result = validateOutput(fullOutput, schema);
         }
+        if (result.errors.length > 0) {
+          throw new Error(`Output of "${node.data.label}" does not match schema: ${result.errors.join('; ')}`);
+        }
+        this.context.set(`node_${node.id}_data`, this.maskSecrets(result.data));
+      } else if (schema && schema.fields.length > 0) {
+        // Best effort: keep whatever manifest parsed, without failing the node
+        const result = validateOutput(fullOutput, schema);
+        if (result.data) this.context.set(`node_${node.id}_data`, this.maskSecrets(result.data));
+      }
+
+      if (cacheKey && cached === undefined && fullOutput) {
+        setCachedResponse(cacheKey, fullOutput, this.responseCacheTtlMs);
       }
     } finally {
+      // Secrets interpolated into the prompt can be echoed back; keep them out of stored output
+      fullOutput = maskSecretValues(capture.text, this.secretValues);
+      if (capture.tokens.input || capture.tokens.output) {
+        this.context.setNodeState(node.id, { tokens: capture.tokens });
+      }
       if (fullOutput) {
-        this.context.setNodeOutput(node.id, fullOutput);
+        this.setNodeOutput(node.id, fullOutput);
         this.context.setNodeState(node.id, { output: fullOutput });
       }
 
       if (workspaceEnabled && this.workspacePath) {
         if (fullOutput) {
           const safeLabel = (node.data.label || node.id).replace(/[^a-zA-Z0-9_-]/g, '_');
-          const outputPath = join(this.workspacePath, `${safeLabel}.md`);
-          writeFileSync(outputPath, fullOutput, 'utf-8');
+          const outputDir = this.artifactsPath || this.workspacePath;
+          mkdirSync(outputDir, { recursive: true });
+          writeFileSync(join(outputDir, `${safeLabel}.md`), fullOutput, 'utf-8');
         }
 
         const filesAfter = this.scanWorkspaceFiles(this.workspacePath);
@@ -306,6 +603,46 @@ export class Executor {
     }
   }
 
+  private async streamAgent(
+    node: WorkflowNode,
+    provider: CodingAgentProvider,
+    messages: ProviderMessage[],
+    options: ProviderOptions,
+    capture: AgentCapture
+  ): Promise<void> {
+    const tracker = node.data.outputSchema ? new PartialOutputTracker(node.data.outputSchema) : null;
+    await acquireRateLimit(`provider:${provider.id}`, this.providerRateLimit, options.signal);
+    await acquireRateLimit(`node:${this.rateLimitScope}:${node.id}`, node.data.rateLimit, options.signal);
+    const stream = provider.stream(messages, options, '');
+    for await (const chunk of stream) {
+      if (chunk.type === 'text') {
+        if (chunk.content) this.outputStarted.add(node.id);
+        capture.text += chunk.content;
+        this.context.emit({
+          type: 'node-output',
+          nodeId: node.id,
+          data: { chunk: chunk.content },
+          timestamp: new Date(),
+        });
+        const parsed = tracker?.push(chunk.content);
+        if (parsed) {
+          const fields = this.maskSecrets(parsed);
+          const previous = this.context.getNodeState(node.id).partialFields;
+          this.context.setNodeState(node.id, { partialFields: { ...previous, ...fields } });
+          this.context.emit({
+            type: 'node-partial-output',
+            nodeId: node.id,
+            data: { fields },
+            timestamp: new Date(),
+          });
+        }
+      } else if (chunk.type === 'done' && chunk.tokens) {
+        capture.tokens.input += chunk.tokens.input;
+        capture.tokens.output += chunk.tokens.output;
+      }
+    }
+  }
+
   private scanWorkspaceFiles(dirPath: string): Map<string, number> {
     const files = new Map<string, number>();
     try {
@@ -357,7 +694,7 @@ export class Executor {
     // If no route matched, use the first route as default
     if (!matchedRoute) matchedRoute = routes[0].label;
 
-    this.context.setNodeOutput(node.id, matchedRoute);
+    this.setNodeOutput(node.id, matchedRoute);
 
     // Skip downstream nodes connected to non-matching routes
     const outgoingEdges = this.graph.getOutgoingEdges(node.id);
@@ -374,21 +711,48 @@ export class Executor {
     const template = node.data.template;
     if (!template) throw new Error('Transform node must have a template');
 
-    // Interpolate {{node_X_output}} and {{variable}} patterns
-    const output = template.replace(/\{\{(\w+)\}\}/g, (_, varName) => {
+    this.setNodeOutput(node.id, this.interpolate(template));
+  }
+
+  /**
+   * Interpolates {{node_X_output}}, {{variable}} and {{variable.path}}
+   * patterns, plus the built-ins {{workflow}} and {{date}}. Unknown names
+   * render empty.
+   */
+  private interpolate(template: string): string {
+    return template.replace(/\{\{(\w+)((?:\.\w+)*)\}\}/g, (_, varName: string, path: string) => {
       // Check node outputs first
       const nodeOutput = this.context.get(`node_${varName}_output`);
-      if (nodeOutput !== undefined) return String(nodeOutput);
-      // Check context variables
-      const contextVal = this.context.get(varName);
-      if (contextVal !== undefined) return String(contextVal);
-      return '';
+      if (nodeOutput !== undefined && !path) return String(nodeOutput);
+      // Check context variables, walking into structured values
+      let value = this.context.get(varName) ?? this.secrets[varName] ?? this.builtinVariable(varName);
+      for (const key of path.split('.').slice(1)) {
+        value = value !== null && typeof value === 'object' ? (value as Record<string, unknown>)[key] : undefined;
+      }
+      if (value === undefined || value === null) return '';
+      return typeof value === 'object' ? JSON.stringify(value) : String(value);
     });
+  }
+
+  // Node outputs end up in the persisted run context, node states and events
+  private setNodeOutput(nodeId: string, output: string): void {
+    this.context.setNodeOutput(nodeId, maskSecretValues(output, this.secretValues));
+  }
+
+  // Masks secret values anywhere in a JSON-serialisable value
+  private maskSecrets<T>(value: T): T {
+    if (this.secretValues.length === 0) return value;
+    const escaped = this.secretValues.map(v => JSON.stringify(v).slice(1, -1));
+    return JSON.parse(maskSecretValues(JSON.stringify(value), escaped)) as T;
+  }
 
-    this.context.setNodeOutput(node.id, output);
+  private builtinVariable(name: string): string | undefined {
+    if (name === 'workflow') return this.workflowName;
+    if (name === 'date') return new Date().toISOString().slice(0, 10);
+    return undefined;
   }
 
-  private async executeGateNode(node: WorkflowNode): Promise<void> {
+  private async executeGateNode(node: WorkflowNode, signal?: AbortSignal): Promise<void> {
     const message = node.data.gateMessage || 'Approval required to continue';
 
     this.context.setNodeState(node.id, { status: 'waiting' });
@@ -406,11 +770,13 @@ export class Executor {
 
     while (Date.now() - startTime < maxWaitMs) {
       if (this.aborted) throw new Error('Run was cancelled');
+      // Node timed out; the race in executeNode has already reported it
+      if (signal?.aborted) return;
 
       // Check if gate has been resolved via context
       const decision = this.context.get(`gate_${node.id}_decision`);
       if (decision === 'approved') {
-        this.context.setNodeOutput(node.id, 'approved');
+        this.setNodeOutput(node.id, 'approved');
         return;
       }
       if (decision === 'rejected') {
@@ -428,7 +794,7 @@ export class Executor {
     if (!condition) throw new Error('Condition node must have a condition expression');
 
     const result = await this.context.evaluateCondition(condition);
-    this.context.setNodeOutput(node.id, String(result));
+    this.setNodeOutput(node.id, String(result));
 
     const outgoingEdges = this.graph.getOutgoingEdges(node.id);
     for (const edge of outgoingEdges) {
@@ -442,9 +808,8 @@ export class Executor {
   }
 
   private executeInputNode(node: WorkflowNode): void {
-    const defaultValue = (node.data as Record<string, unknown>).defaultValue as string || '';
-    const inputData = this.context.get('input') as string || defaultValue;
-    this.context.setNodeOutput(node.id, inputData);
+    const inputData = this.context.get(inputVariableName(node)) as string || node.data.defaultValue || '';
+    this.setNodeOutput(node.id, inputData);
   }
 
   private executeOutputNode(node: WorkflowNode): void {
@@ -453,7 +818,7 @@ export class Executor {
       .map(p => this.context.getNodeOutput(p))
       .filter(Boolean)
       .join('\n\n');
-    this.context.setNodeOutput(node.id, outputs);
+    this.setNodeOutput(node.id, outputs);
     this.context.set('output', outputs);
   }
 
@@ -470,7 +835,7 @@ export class Executor {
         if (!shouldContinue) break;
       }
 
-      this.context.setNodeOutput(node.id, `Loop iteration ${iteration}`);
+      this.setNodeOutput(node.id, `Loop iteration ${iteration}`);
     }
   }
 }
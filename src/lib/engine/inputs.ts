@@ -0,0 +1,75 @@
+import type { WorkflowNode } from './types';
+
+export interface ResolvedInputs {
+  values: Record<string, string>;
+  errors: string[];
+}
+
+export class InputValidationError extends Error {
+  constructor(readonly errors: string[]) {
+    super(`Invalid inputs: ${errors.join(', ')}`);
+    this.name = 'InputValidationError';
+  }
+}
+
+export function inputVariableName(node: WorkflowNode): string {
+  return node.data.variableName?.trim() || 'input';
+}
+
+function checkType(value: string, type: WorkflowNode['data']['inputType']): boolean {
+  switch (type) {
+    case 'number':
+      return value.trim() !== '' && Number.isFinite(Number(value));
+    case 'boolean':
+      return value === 'true' || value === 'false';
+    case 'json':
+      try {
+        JSON.parse(value);
+        return true;
+      } catch {
+        return false;
+      }
+    default:
+      return true;
+  }
+}
+
+/**
+ * Resolves each input node's value from the supplied variables, falling back
+ * to its default, and reports missing required inputs and type mismatches.
+ */
+export function resolveInputs(nodes: WorkflowNode[], variables: Record<string, unknown>): ResolvedInputs {
+  const values: Record<string, string> = {};
+  const errors: string[] = [];
+
+  for (const node of nodes) {
+    if (node.type !== 'input') continue;
+
+    const name = inputVariableName(node);
+    const supplied = variables[name];
+    const value = supplied !== undefined && supplied !== null && String(supplied) !== ''
+      ? String(supplied)
+      : node.data.defaultValue || '';
+
+    if (value === '') {
+      if (node.data.required) errors.push(`Missing required input "${name}"`);
+      continue;
+    }
+
+    if (!checkType(value, node.data.inputType)) {
+      errors.push(`Input "${name}" must be ${node.data.inputType === 'json' ? 'valid JSON' : `a ${node.data.inputType}`}`);
+      continue;
+    }
+
+    values[name] = value;
+  }
+
+  return { values, errors };
+}
+
+/** Like resolveInputs, but throws InputValidationError so callers can reject the run up front. */
+export function requireValidInputs(nodes: WorkflowNode[], variables: Record<string, unknown>): Record<string, string> {
+  const { values, errors } = resolveInputs(nodes, variables);
+  if (errors.length > 0) throw new InputValidationError(errors);
+  return values;
+}
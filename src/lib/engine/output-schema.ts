@@ -0,0 +1,303 @@
+import type { OutputField, OutputSchema } from './types';
+
+export interface OutputValidationResult {
+  data?: Record<string, unknown>;
+  errors: string[];
+}
+
+const MANIFEST_FENCE = 'output-manifest';
+
+/**
+ * Prompt instructions asking the agent to end its response with a
+ * fenced output-manifest block matching the schema.
+ */
+export function buildOutputSchemaInstructions(schema: OutputSchema): string {
+  const lines = schema.fields.map(f => {
+    const required = f.required ? 'required' : 'optional';
+    const description = f.description ? ` — ${f.description}` : '';
+    return `- ${f.name} (${f.type}, ${required})${description}`;
+  });
+
+  return [
+    'End your response with a fenced code block tagged `' + MANIFEST_FENCE + '` containing a single JSON object with these fields:',
+    ...lines,
+    '',
+    'Example:',
+    '```' + MANIFEST_FENCE,
+    '{ ... }',
+    '```',
+  ].join('\n');
+}
+
+/**
+ * Returns the contents of the last output-manifest (or json) fenced block,
+ * falling back to a trailing bare JSON object.
+ */
+export function extractLastJsonBlock(text: string): string | null {
+  const fence = /```([\w-]*)[ \t]*\n([\s\S]*?)```/g;
+  let manifest: string | null = null;
+  let json: string | null = null;
+
+  let match: RegExpExecArray | null;
+  while ((match = fence.exec(text)) !== null) {
+    const tag = match[1].toLowerCase();
+    if (tag === MANIFEST_FENCE) manifest = match[2];
+    else if (tag === 'json') json = match[2];
+  }
+
+  const block = manifest ?? json;
+  if (block !== null) return block.trim();
+
+  const trimmed = text.trimEnd();
+  if (!trimmed.endsWith('}')) return null;
+  const start = trimmed.lastIndexOf('\n{');
+  const candidate = start >= 0 ? trimmed.slice(start + 1) : trimmed;
+  return candidate.startsWith('{') ? candidate : null;
+}
+
+function isPlainObject(value: unknown): value is Record<string, unknown> {
+  return typeof value === 'object' && value !== null && !Array.isArray(value);
+}
+
+function matchesType(value: unknown, type: OutputField['type']): boolean {
+  switch (type) {
+    case 'string':
+      return typeof value === 'string';
+    case 'number':
+      return typeof value === 'number' && Number.isFinite(value);
+    case 'integer':
+      return typeof value === 'number' && Number.isInteger(value);
+    case 'boolean':
+      return typeof value === 'boolean';
+    case 'string[]':
+      return Array.isArray(value) && value.every(v => typeof v === 'string');
+    case 'number[]':
+      return Array.isArray(value) && value.every(v => typeof v === 'number' && Number.isFinite(v));
+    case 'object':
+      return isPlainObject(value);
+    case 'object[]':
+      return Array.isArray(value) && value.every(isPlainObject);
+    default:
+      return false;
+  }
+}
+
+function describeType(value: unknown): string {
+  if (value === null) return 'null';
+  if (Array.isArray(value)) return 'array';
+  return typeof value;
+}
+
+/**
+ * Checks a parsed manifest against the schema. Returns one message per violation.
+ */
+export function validateManifest(manifest: Record<string, unknown>, schema: OutputSchema): string[] {
+  const errors: string[] = [];
+
+  for (const field of schema.fields) {
+    const value = manifest[field.name];
+    if (value === undefined || value === null) {
+      if (field.required) errors.push(`Missing required field "${field.name}"`);
+      continue;
+    }
+    if (!matchesType(value, field.type)) {
+      errors.push(`Field "${field.name}" must be ${field.type}, got ${describeType(value)}`);
+    }
+  }
+
+  return errors;
+}
+
+/**
+ * Fixes the slips models tend to make in otherwise well-formed JSON:
+ * trailing commas, unquoted keys, and a string or brackets left unclosed.
+ * Returns the input unchanged where there is nothing to fix; the result
+ * still has to go through JSON.parse.
+ */
+export function repairJson(text: string): string {
+  let out = '';
+  const open: string[] = [];
+  let inString = false;
+  let escaped = false;
+  // Last character outside strings that isn't whitespace
+  let previous = '';
+
+  for (let i = 0; i < text.length; i++) {
+    const ch = text[i];
+    if (inString) {
+      out += ch;
+      if (escaped) escaped = false;
+      else if (ch === '\\') escaped = true;
+      else if (ch === '"') inString = false;
+      continue;
+    }
+
+    if (ch === '"') {
+      inString = true;
+    } else if (ch === '{' || ch === '[') {
+      open.push(ch === '{' ? '}' : ']');
+    } else if (ch === '}' || ch === ']') {
+      if (open[open.length - 1] === ch) open.pop();
+    } else if (ch === ',') {
+      const next = text.slice(i + 1).trimStart()[0];
+      if (next === undefined || next === '}' || next === ']') continue;
+    } else if (/[A-Za-z_$]/.test(ch) && (previous === '{' || previous === ',')) {
+      const key = /^[A-Za-z_$][\w$]*/.exec(text.slice(i))![0];
+      if (/^\s*:/.test(text.slice(i + key.length))) {
+        out += `"${key}"`;
+        i += key.length - 1;
+        previous = '"';
+        continue;
+      }
+    }
+
+    out += ch;
+    if (!/\s/.test(ch)) previous = ch;
+  }
+
+  if (inString) out += '"';
+  return out + open.reverse().join('');
+}
+
+/**
+ * Extracts the output manifest from an agent response and validates it.
+ * A manifest that isn't valid JSON gets one repair attempt before it's
+ * reported as invalid.
+ */
+export function validateOutput(text: string, schema: OutputSchema): OutputValidationResult {
+  const block = extractLastJsonBlock(text);
+  if (block === null) {
+    return { errors: [`No ${MANIFEST_FENCE} block found in response`] };
+  }
+
+  let parsed: unknown;
+  try {
+    parsed = JSON.parse(block);
+  } catch (error) {
+    try {
+      parsed = JSON.parse(repairJson(block));
+    } catch {
+      return { errors: [`Output manifest is not valid JSON: ${error instanceof Error ? error.message : String(error)}`] };
+    }
+  }
+
+  if (!isPlainObject(parsed)) {
+    return { errors: ['Output manifest must be a JSON object'] };
+  }
+
+  return { data: parsed, errors: validateManifest(parsed, schema) };
+}
+
+/**
+ * Follow-up prompt sent once when a strict schema is violated.
+ */
+export function buildCorrectionPrompt(previousOutput: string, errors: string[]): string {
+  return [
+    'Your previous response did not satisfy the required output schema:',
+    ...errors.map(e => `- ${e}`),
+    '',
+    'Previous response:',
+    previousOutput,
+    '',
+    `Respond again, ending with a corrected ${MANIFEST_FENCE} block.`,
+  ].join('\n');
+}
+
+/**
+ * Top-level members of a manifest block that is still being streamed: the
+ * last output-manifest or json fence that hasn't been closed yet. A member
+ * counts once the comma or brace after it has arrived.
+ */
+export function parseOpenManifest(text: string): Record<string, unknown> | null {
+  const opening = /```(output-manifest|json)[ \t]*\n/gi;
+  let start = -1;
+  let match: RegExpExecArray | null;
+  while ((match = opening.exec(text)) !== null) start = match.index + match[0].length;
+  if (start < 0 || text.indexOf('```', start) >= 0) return null;
+
+  const body = text.slice(start).trimStart();
+  if (!body.startsWith('{')) return null;
+
+  const members: string[] = [];
+  let depth = 0;
+  let inString = false;
+  let escaped = false;
+  let memberStart = 1;
+  for (let i = 0; i < body.length; i++) {
+    const ch = body[i];
+    if (inString) {
+      if (escaped) escaped = false;
+      else if (ch === '\\') escaped = true;
+      else if (ch === '"') inString = false;
+      continue;
+    }
+    if (ch === '"') inString = true;
+    else if (ch === '{' || ch === '[') depth++;
+    else if (ch === '}' || ch === ']') {
+      depth--;
+      if (depth === 0) {
+        members.push(body.slice(memberStart, i));
+        break;
+      }
+    } else if (ch === ',' && depth === 1) {
+      members.push(body.slice(memberStart, i));
+      memberStart = i + 1;
+    }
+  }
+
+  const closed = members.filter(m => m.trim());
+  if (closed.length === 0) return null;
+  try {
+    const parsed = JSON.parse(`{${closed.join(',')}}`);
+    return isPlainObject(parsed) ? parsed : null;
+  } catch {
+    return null;
+  }
+}
+
+/**
+ * Buffers streamed text and re-parses the trailing manifest as chunks
+ * arrive, reporting fields that became available or changed, including
+ * fields of a manifest that is still open. The final validateOutput on the
+ * complete response remains authoritative.
+ */
+export class PartialOutputTracker {
+  private buffer = '';
+  private seen = new Map<string, string>();
+
+  constructor(private schema?: OutputSchema) {}
+
+  push(chunk: string): Record<string, unknown> | null {
+    this.buffer += chunk;
+    // A block or member can only complete on a closing brace, comma or fence
+    if (!chunk.includes('}') && !chunk.includes(',') && !chunk.includes('`')) return null;
+
+    const parsed = this.parseComplete() ?? parseOpenManifest(this.buffer);
+    if (!parsed) return null;
+
+    const allowed = this.schema ? new Set(this.schema.fields.map(f => f.name)) : null;
+    const fields: Record<string, unknown> = {};
+    let changed = false;
+    for (const [name, value] of Object.entries(parsed)) {
+      if (allowed && !allowed.has(name)) continue;
+      const serialized = JSON.stringify(value);
+      if (this.seen.get(name) === serialized) continue;
+      this.seen.set(name, serialized);
+      fields[name] = value;
+      changed = true;
+    }
+
+    return changed ? fields : null;
+  }
+
+  private parseComplete(): Record<string, unknown> | null {
+    const block = extractLastJsonBlock(this.buffer);
+    if (block === null) return null;
+    try {
+      const parsed = JSON.parse(block);
+      return isPlainObject(parsed) ? parsed : null;
+    } catch {
+      return null;
+    }
+  }
+}
@@ -0,0 +1,270 @@
+import type { NodeRunState, RunState, WorkflowNode } from './types';
+import { summarizeRetries, type RetrySummary } from './retry';
+
+export interface ReportSource {
+  id: string;
+  workflowId: string;
+  workflowName: string;
+  status: string;
+  startedAt: Date | string;
+  completedAt?: Date | string | null;
+  context?: Record<string, unknown> | null;
+  nodeStates?: Record<string, NodeRunState> | null;
+  tokenUsage?: RunState['totalTokens'] | null;
+  nodes?: WorkflowNode[];
+}
+
+export interface ReportNode {
+  id: string;
+  label: string;
+  type?: WorkflowNode['type'];
+  status: NodeRunState['status'];
+  startedAt?: string;
+  completedAt?: string;
+  durationMs?: number;
+  tokens?: { input: number; output: number };
+  attempts?: number;
+  output?: string;
+  error?: string;
+}
+
+export interface RunReport {
+  runId: string;
+  workflowId: string;
+  workflowName: string;
+  status: string;
+  startedAt: string;
+  completedAt?: string;
+  durationMs?: number;
+  inputs: Record<string, unknown>;
+  nodes: ReportNode[];
+  totalTokens: RunState['totalTokens'];
+  retries: RetrySummary;
+  output?: string;
+}
+
+const STATUS_EMOJI: Record<string, string> = {
+  completed: '✅',
+  failed: '❌',
+  skipped: '⏭️',
+  running: '⏳',
+  waiting: '✋',
+  pending: '⏸️',
+  cancelled: '🚫',
+};
+
+// Context keys written by the executor rather than supplied as inputs
+const INTERNAL_KEY = /^(node_.+_(output|data)|gate_.+_decision|loop_.+_iteration|output|error|_.*)$/;
+
+function toIso(value: Date | string | null | undefined): string | undefined {
+  if (!value) return undefined;
+  return new Date(value).toISOString();
+}
+
+function durationBetween(start?: string, end?: string): number | undefined {
+  if (!start || !end) return undefined;
+  return new Date(end).getTime() - new Date(start).getTime();
+}
+
+export function formatDuration(ms?: number): string {
+  if (ms === undefined) return '-';
+  if (ms < 1000) return `${ms}ms`;
+  if (ms < 60000) return `${(ms / 1000).toFixed(1)}s`;
+  return `${Math.floor(ms / 60000)}m ${Math.round((ms % 60000) / 1000)}s`;
+}
+
+/**
+ * Assembles a shareable summary from a stored run.
+ */
+export function buildRunReport(source: ReportSource): RunReport {
+  const context = source.context || {};
+  const nodeStates = source.nodeStates || {};
+  const nodeMap = new Map<string, WorkflowNode>((source.nodes || []).map(n => [n.id, n]));
+
+  const inputs: Record<string, unknown> = {};
+  for (const [key, value] of Object.entries(context)) {
+    if (!INTERNAL_KEY.test(key)) inputs[key] = value;
+  }
+
+  // Graph order first, then any states for nodes no longer in the graph
+  const ids = [
+    ...(source.nodes || []).map(n => n.id).filter(id => id in nodeStates),
+    ...Object.keys(nodeStates).filter(id => !nodeMap.has(id)),
+  ];
+
+  const nodes: ReportNode[] = ids.map(id => {
+    const state = nodeStates[id];
+    const node = nodeMap.get(id);
+    const startedAt = toIso(state.startedAt);
+    const completedAt = toIso(state.completedAt);
+    return {
+      id,
+      label: node?.data.label || id,
+      type: node?.type,
+      status: state.status,
+      startedAt,
+      completedAt,
+      durationMs: durationBetween(startedAt, completedAt),
+      tokens: state.tokens,
+      attempts: state.attempts,
+      output: state.output,
+      error: state.error,
+    };
+  });
+
+  const startedAt = toIso(source.startedAt)!;
+  const completedAt = toIso(source.completedAt);
+
+  return {
+    runId: source.id,
+    workflowId: source.workflowId,
+    workflowName: source.workflowName,
+    status: source.status,
+    startedAt,
+    completedAt,
+    durationMs: durationBetween(startedAt, completedAt),
+    inputs,
+    nodes,
+    totalTokens: source.tokenUsage || { input: 0, output: 0, cost: 0 },
+    retries: summarizeRetries(nodeStates),
+    output: typeof context.output === 'string' ? context.output : undefined,
+  };
+}
+
+type NodeField = 'status' | 'output' | 'durationMs' | 'tokens';
+type RunField = 'status' | 'output' | 'durationMs' | 'totalTokens';
+
+export interface NodeComparison {
+  id: string;
+  label: string;
+  // Present in only one of the runs, e.g. after the graph was edited
+  onlyIn?: 'a' | 'b';
+  changed: NodeField[];
+  a?: ReportNode;
+  b?: ReportNode;
+}
+
+export interface RunComparison {
+  a: Pick<RunReport, 'runId' | 'status' | 'durationMs' | 'totalTokens' | 'output'>;
+  b: Pick<RunReport, 'runId' | 'status' | 'durationMs' | 'totalTokens' | 'output'>;
+  changed: RunField[];
+  nodes: NodeComparison[];
+}
+
+function differs(a: unknown, b: unknown): boolean {
+  return JSON.stringify(a) !== JSON.stringify(b);
+}
+
+function summarize(report: RunReport): RunComparison['a'] {
+  const { runId, status, durationMs, totalTokens, output } = report;
+  return { runId, status, durationMs, totalTokens, output };
+}
+
+/**
+ * Field-level diff of two run reports, typically two runs of the same
+ * workflow. Nodes follow run a's order, then nodes only present in run b.
+ */
+export function compareReports(a: RunReport, b: RunReport): RunComparison {
+  const runFields: RunField[] = ['status', 'output', 'durationMs', 'totalTokens'];
+  const nodeFields: NodeField[] = ['status', 'output', 'durationMs', 'tokens'];
+  const bNodes = new Map(b.nodes.map(n => [n.id, n]));
+  const aIds = new Set(a.nodes.map(n => n.id));
+
+  const nodes: NodeComparison[] = a.nodes.map(nodeA => {
+    const nodeB = bNodes.get(nodeA.id);
+    if (!nodeB) return { id: nodeA.id, label: nodeA.label, onlyIn: 'a', changed: [], a: nodeA };
+    return {
+      id: nodeA.id,
+      label: nodeA.label,
+      changed: nodeFields.filter(f => differs(nodeA[f], nodeB[f])),
+      a: nodeA,
+      b: nodeB,
+    };
+  });
+  for (const nodeB of b.nodes) {
+    if (!aIds.has(nodeB.id)) nodes.push({ id: nodeB.id, label: nodeB.label, onlyIn: 'b', changed: [], b: nodeB });
+  }
+
+  return {
+    a: summarize(a),
+    b: summarize(b),
+    changed: runFields.filter(f => differs(a[f], b[f])),
+    nodes,
+  };
+}
+
+function escapeCell(value: string): string {
+  return value.replace(/\|/g, '\\|').replace(/\n/g, ' ');
+}
+
+export function renderReportMarkdown(report: RunReport): string {
+  const emoji = (status: string) => STATUS_EMOJI[status] || '•';
+  const lines: string[] = [];
+
+  lines.push(`# ${report.workflowName} — run ${report.runId.slice(0, 8)}`);
+  lines.push('');
+  lines.push(`- **Status:** ${emoji(report.status)} ${report.status}`);
+  lines.push(`- **Started:** ${report.startedAt}`);
+  if (report.completedAt) lines.push(`- **Completed:** ${report.completedAt}`);
+  lines.push(`- **Duration:** ${formatDuration(report.durationMs)}`);
+  const { input, output, cost } = report.totalTokens;
+  lines.push(`- **Tokens:** ${input + output} (${input} in / ${output} out) · $${cost.toFixed(4)}`);
+  if (report.retries.retries > 0) {
+    const { retries, recovered, exhausted } = report.retries;
+    lines.push(`- **Retries:** ${retries} (${recovered} recovered, ${exhausted} exhausted)`);
+  }
+
+  const inputEntries = Object.entries(report.inputs);
+  if (inputEntries.length > 0) {
+    lines.push('');
+    lines.push('## Inputs');
+    lines.push('');
+    for (const [key, value] of inputEntries) {
+      lines.push(`- \`${key}\`: ${typeof value === 'string' ? value : JSON.stringify(value)}`);
+    }
+  }
+
+  lines.push('');
+  lines.push('## Nodes');
+  lines.push('');
+  lines.push('| Node | Type | Status | Duration | Tokens |');
+  lines.push('|------|------|--------|----------|--------|');
+  for (const node of report.nodes) {
+    const tokens = node.tokens ? String(node.tokens.input + node.tokens.output) : '-';
+    lines.push(`| ${escapeCell(node.label)} | ${node.type || '-'} | ${emoji(node.status)} ${node.status} | ${formatDuration(node.durationMs)} | ${tokens} |`);
+  }
+
+  const timeline = report.nodes
+    .filter(n => n.startedAt)
+    .sort((a, b) => a.startedAt!.localeCompare(b.startedAt!));
+  if (timeline.length > 0) {
+    lines.push('');
+    lines.push('## Timeline');
+    lines.push('');
+    for (const node of timeline) {
+      const end = node.completedAt ? ` → ${node.completedAt} (${formatDuration(node.durationMs)})` : '';
+      lines.push(`- ${node.startedAt}${end} ${emoji(node.status)} **${node.label}**${node.error ? ` — ${node.error}` : ''}`);
+    }
+  }
+
+  const withOutput = report.nodes.filter(n => n.output);
+  if (withOutput.length > 0) {
+    lines.push('');
+    lines.push('## Node Outputs');
+    for (const node of withOutput) {
+      lines.push('');
+      lines.push(`### ${node.label}`);
+      lines.push('');
+      lines.push(node.output!);
+    }
+  }
+
+  if (report.output) {
+    lines.push('');
+    lines.push('## Final Output');
+    lines.push('');
+    lines.push(report.output);
+  }
+
+  return lines.join('\n') + '\n';
+}
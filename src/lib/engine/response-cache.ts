@@ -0,0 +1,44 @@
+import { createHash } from 'crypto';
+import type { ProviderMessage } from '@/types/provider';
+
+export const DEFAULT_RESPONSE_CACHE_TTL_MS = 60 * 60_000;
+
+interface Entry {
+  text: string;
+  expiresAt: number;
+}
+
+const store = new Map<string, Entry>();
+
+// Cleanup expired responses every 5 minutes
+const cleanupInterval = setInterval(() => {
+  const now = Date.now();
+  for (const [key, entry] of store) {
+    if (entry.expiresAt <= now) store.delete(key);
+  }
+}, 300_000);
+cleanupInterval.unref();
+
+export function responseCacheKey(providerId: string, messages: ProviderMessage[], maxTurns?: number, model?: string): string {
+  return createHash('sha256')
+    .update(JSON.stringify({ providerId, messages, maxTurns: maxTurns ?? null, model: model ?? null }))
+    .digest('hex');
+}
+
+export function getCachedResponse(key: string): string | undefined {
+  const entry = store.get(key);
+  if (!entry) return undefined;
+  if (entry.expiresAt <= Date.now()) {
+    store.delete(key);
+    return undefined;
+  }
+  return entry.text;
+}
+
+export function setCachedResponse(key: string, text: string, ttlMs: number): void {
+  store.set(key, { text, expiresAt: Date.now() + ttlMs });
+}
+
+export function clearResponseCache(): void {
+  store.clear();
+}
@@ -0,0 +1,58 @@
+import type { JitterMode, NodeRunState } from './types';
+
+export interface RetryPolicy {
+  baseDelayMs: number;
+  maxDelayMs: number;
+  jitter: JitterMode;
+  // Spread for proportional jitter, e.g. 0.2 = ±20%
+  jitterFraction: number;
+}
+
+export const DEFAULT_RETRY_POLICY: RetryPolicy = {
+  baseDelayMs: 1000,
+  maxDelayMs: 60_000,
+  jitter: 'none',
+  jitterFraction: 0.2,
+};
+
+/**
+ * Delay before retry number `attempt` (0-based): exponential backoff from
+ * baseDelayMs, capped at maxDelayMs, then jittered. `random` returns [0, 1)
+ * and is injectable for deterministic tests.
+ */
+export function computeBackoff(
+  attempt: number,
+  policy: RetryPolicy = DEFAULT_RETRY_POLICY,
+  random: () => number = Math.random
+): number {
+  const delay = Math.min(policy.baseDelayMs * Math.pow(2, attempt), policy.maxDelayMs);
+
+  switch (policy.jitter) {
+    case 'full':
+      return Math.floor(random() * delay);
+    case 'proportional':
+      return Math.max(0, Math.round(delay * (1 + policy.jitterFraction * (2 * random() - 1))));
+    default:
+      return delay;
+  }
+}
+
+export interface RetrySummary {
+  // Extra attempts made across all nodes
+  retries: number;
+  // Nodes that failed at least once and then completed
+  recovered: number;
+  // Nodes that failed on every attempt
+  exhausted: number;
+}
+
+export function summarizeRetries(nodeStates: Record<string, NodeRunState>): RetrySummary {
+  const summary: RetrySummary = { retries: 0, recovered: 0, exhausted: 0 };
+  for (const state of Object.values(nodeStates)) {
+    if (!state.attempts || state.attempts < 2) continue;
+    summary.retries += state.attempts - 1;
+    if (state.status === 'completed') summary.recovered++;
+    if (state.status === 'failed') summary.exhausted++;
+  }
+  return summary;
+}
@@ -0,0 +1,47 @@
+import type { ExecutionEvent } from './types';
+
+// Streamed output chunks are left out; the final output is kept in node states
+export const PERSISTED_EVENT_TYPES = new Set<ExecutionEvent['type']>([
+  'node-start',
+  'node-retry',
+  'node-complete',
+  'node-error',
+  'node-waiting',
+  'run-progress',
+  'run-paused',
+  'run-resumed',
+  'run-complete',
+  'run-error',
+]);
+
+export interface StoredRunEvent {
+  seq: number;
+  type: ExecutionEvent['type'];
+  nodeId: string | null;
+  data: Record<string, unknown>;
+  createdAt: Date;
+}
+
+/**
+ * Shapes an executor event for the run_events table, or returns null when
+ * the type isn't persisted. Bulky payloads that are already stored on the
+ * run row (outputs, full run state) are reduced to their status.
+ */
+export function toStoredEvent(event: ExecutionEvent, seq: number): StoredRunEvent | null {
+  if (!PERSISTED_EVENT_TYPES.has(event.type)) return null;
+
+  const raw = (event.data && typeof event.data === 'object' ? event.data : {}) as Record<string, unknown>;
+  let data: Record<string, unknown>;
+  switch (event.type) {
+    case 'node-complete':
+      data = {};
+      break;
+    case 'run-complete':
+      data = { status: raw.status };
+      break;
+    default:
+      data = raw;
+  }
+
+  return { seq, type: event.type, nodeId: event.nodeId ?? null, data, createdAt: event.timestamp };
+}
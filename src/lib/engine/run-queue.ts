@@ -0,0 +1,25 @@
+export class RunQueueFullError extends Error {
+  constructor(readonly limit: number) {
+    super(`Run queue is full (limit ${limit} queued runs)`);
+    this.name = 'RunQueueFullError';
+  }
+}
+
+export interface QueuedRun {
+  id: string;
+  priority: number;
+  // When the run was queued
+  startedAt: Date | string;
+}
+
+/**
+ * Dispatch order for queued runs: higher priority first, then first come,
+ * first served. Returns a new array.
+ */
+export function orderQueue<T extends QueuedRun>(queued: T[]): T[] {
+  return [...queued].sort((a, b) =>
+    b.priority - a.priority ||
+    new Date(a.startedAt).getTime() - new Date(b.startedAt).getTime() ||
+    a.id.localeCompare(b.id)
+  );
+}
@@ -1,9 +1,16 @@
 import { db } from '@/lib/db';
-import { workflows, runs } from '@/lib/db/schema';
-import { eq, and } from 'drizzle-orm';
+import { workflows, runs, runEvents } from '@/lib/db/schema';
+import { eq, and, sql } from 'drizzle-orm';
 import { Graph } from './graph';
 import { Executor } from './executor';
-import type { WorkflowNode, WorkflowEdge, ExecutionEvent } from './types';
+import { requireValidInputs } from './inputs';
+import { toStoredEvent } from './run-events';
+import { orderQueue, RunQueueFullError } from './run-queue';
+import { registerActiveRun, drainActiveRuns, isDraining, claimRunSlot, RunCapacityError } from './active-runs';
+import { logger } from '@/lib/logger';
+import { getConfig } from '@/lib/config';
+import { resolveSecretVariables } from '@/lib/secrets';
+import type { WorkflowNode, WorkflowEdge, ExecutionEvent, RunOverrides } from './types';
 import { homedir } from 'os';
 import { mkdirSync } from 'fs';
 import { join } from 'path';
@@ -13,7 +20,77 @@ interface RunResult {
   status: string;
 }
 
-export async function startWorkflowRun(workflowId: string, userId: string): Promise<RunResult> {
+export interface StartRunOptions {
+  inputs?: Record<string, string>;
+  labels?: Record<string, string>;
+  overrides?: RunOverrides;
+  // Dispatch order when the run has to wait for a slot; higher goes first
+  priority?: number;
+}
+
+interface PreparedRun {
+  workflow: typeof workflows.$inferSelect;
+  graphData: { nodes: WorkflowNode[]; edges: WorkflowEdge[] };
+  variables: Record<string, string>;
+  secrets: Record<string, string>;
+}
+
+export async function startWorkflowRun(
+  workflowId: string,
+  userId: string,
+  options: StartRunOptions = {}
+): Promise<RunResult> {
+  if (isDraining()) {
+    throw new Error('Server is shutting down');
+  }
+
+  const { maxConcurrentRuns, runQueueSize } = getConfig().engine;
+  // Runs already waiting go first; a new start only takes a free slot when nobody is queued
+  if (runQueueSize > 0 && await hasQueuedRuns()) {
+    return enqueueRun(workflowId, userId, options, runQueueSize);
+  }
+
+  let releaseSlot: () => void;
+  try {
+    releaseSlot = claimRunSlot(maxConcurrentRuns);
+  } catch (err) {
+    if (!(err instanceof RunCapacityError) || runQueueSize <= 0) throw err;
+    return enqueueRun(workflowId, userId, options, runQueueSize);
+  }
+
+  try {
+    const prepared = await prepareRun(workflowId, userId, options.inputs);
+
+    // Create run record
+    const [run] = await db
+      .insert(runs)
+      .values({
+        workflowId,
+        userId,
+        status: 'running',
+        context: {},
+        nodeStates: {},
+        tokenUsage: { input: 0, output: 0, cost: 0 },
+        labels: options.labels || {},
+        overrides: options.overrides || {},
+        inputs: options.inputs || {},
+        priority: options.priority ?? 0,
+        startedAt: new Date(),
+      })
+      .returning();
+
+    launchRun(run.id, prepared, options.overrides);
+    return { runId: run.id, status: 'running' };
+  } finally {
+    releaseSlot();
+  }
+}
+
+/**
+ * Loads and validates everything a run needs, so bad requests fail before a
+ * run record exists (or, for queued runs, before they are accepted).
+ */
+async function prepareRun(workflowId: string, userId: string, inputs?: Record<string, string>): Promise<PreparedRun> {
   // Fetch workflow
   const [workflow] = await db
     .select()
@@ -36,33 +113,179 @@ export async function startWorkflowRun(workflowId: string, userId: string): Prom
     throw new Error(`Invalid workflow: ${validation.errors.join(', ')}`);
   }
 
+  // Resolve secret references before creating the run so a missing secret fails the request
+  const { variables, secrets } = resolveSecretVariables(
+    { ...(workflow.variables as Record<string, string>), ...inputs },
+    { secretsDir: getConfig().engine.secretsDir }
+  );
+  // Reject missing or mistyped inputs now rather than failing the run once started
+  requireValidInputs(graphData.nodes, variables);
+
+  return { workflow, graphData, variables, secrets };
+}
+
+// Serializes the queue size check with the insert, across server processes
+const QUEUE_LOCK_ID = 876_001;
+
+async function hasQueuedRuns(): Promise<boolean> {
+  const [queued] = await db.select({ id: runs.id }).from(runs).where(eq(runs.status, 'queued')).limit(1);
+  return queued !== undefined;
+}
+
+async function enqueueRun(
+  workflowId: string,
+  userId: string,
+  options: StartRunOptions,
+  queueSize: number
+): Promise<RunResult> {
+  await prepareRun(workflowId, userId, options.inputs);
+
+  const run = await db.transaction(async (tx) => {
+    await tx.execute(sql`select pg_advisory_xact_lock(${QUEUE_LOCK_ID})`);
+    const [{ queued }] = await tx
+      .select({ queued: sql<number>`count(*)::int` })
+      .from(runs)
+      .where(eq(runs.status, 'queued'));
+    if (queued >= queueSize) {
+      throw new RunQueueFullError(queueSize);
+    }
+
+    const [inserted] = await tx
+      .insert(runs)
+      .values({
+        workflowId,
+        userId,
+        status: 'queued',
+        context: {},
+        nodeStates: {},
+        tokenUsage: { input: 0, output: 0, cost: 0 },
+        labels: options.labels || {},
+        overrides: options.overrides || {},
+        inputs: options.inputs || {},
+        priority: options.priority ?? 0,
+        startedAt: new Date(),
+      })
+      .returning({ id: runs.id });
+    return inserted;
+  });
+
+  // Queued while a slot may be free (others were waiting); let the dispatcher decide
+  void dispatchQueuedRuns();
+  return { runId: run.id, status: 'queued' };
+}
+
+let dispatching = false;
+let redispatch = false;
+
+/**
+ * Starts queued runs, highest priority first, while slots are free. Called
+ * whenever a run finishes or is queued, and on server start, so queued runs
+ * survive a restart. A call made mid-dispatch makes the dispatcher take
+ * another pass rather than being dropped.
+ */
+export async function dispatchQueuedRuns(): Promise<void> {
+  if (dispatching) {
+    redispatch = true;
+    return;
+  }
+  dispatching = true;
+  try {
+    do {
+      redispatch = false;
+      await dispatchWhileFree();
+    } while (redispatch && !isDraining());
+  } catch (err) {
+    logger.error('Failed to dispatch queued runs', { error: String(err) });
+  } finally {
+    dispatching = false;
+  }
+}
+
+async function dispatchWhileFree(): Promise<void> {
+  while (!isDraining()) {
+    let releaseSlot: () => void;
+    try {
+      releaseSlot = claimRunSlot(getConfig().engine.maxConcurrentRuns);
+    } catch {
+      return;
+    }
+
+    try {
+      const queued = await db
+        .select({ id: runs.id, priority: runs.priority, startedAt: runs.startedAt })
+        .from(runs)
+        .where(eq(runs.status, 'queued'));
+      const [next] = orderQueue(queued);
+      if (!next) return;
+
+      // Conditional update so a run cancelled meanwhile (or taken by another process) is skipped
+      const [run] = await db
+        .update(runs)
+        .set({ status: 'running', startedAt: new Date() })
+        .where(and(eq(runs.id, next.id), eq(runs.status, 'queued')))
+        .returning();
+      if (!run) continue;
+
+      try {
+        const prepared = await prepareRun(run.workflowId, run.userId, run.inputs as Record<string, string>);
+        launchRun(run.id, prepared, run.overrides as RunOverrides);
+      } catch (err) {
+        logger.warn('Queued run failed to start', { runId: run.id, error: String(err) });
+        await db
+          .update(runs)
+          .set({
+            status: 'failed',
+            context: { error: err instanceof Error ? err.message : String(err) },
+            completedAt: new Date(),
+          })
+          .where(eq(runs.id, run.id));
+      }
+    } finally {
+      releaseSlot();
+    }
+  }
+}
+
+function launchRun(runId: string, prepared: PreparedRun, overrides?: RunOverrides): void {
+  const { workflow, graphData, variables, secrets } = prepared;
+  const workflowId = workflow.id;
+
   // Setup workspace
   const workspacePath = join(homedir(), '.cadre', 'workspaces', workflowId);
   try { mkdirSync(workspacePath, { recursive: true }); } catch { /* exists */ }
 
-  // Create run record
-  const [run] = await db
-    .insert(runs)
-    .values({
-      workflowId,
-      userId,
-      status: 'running',
-      context: {},
-      nodeStates: {},
-      tokenUsage: { input: 0, output: 0, cost: 0 },
-      startedAt: new Date(),
-    })
-    .returning();
-
-  const variables = (workflow.variables as Record<string, string>) || {};
+  const log = logger.child({ runId, workflowId });
+  const { artifactsDir } = getConfig().engine;
+  let eventSeq = 0;
 
   // Execute in background (don't await — return immediately)
   const executor = new Executor(graphData.nodes, graphData.edges, {
     variables,
+    secrets,
     workspacePath,
+    artifactsPath: artifactsDir ? join(workspacePath, artifactsDir, runId) : undefined,
+    runId,
+    workflowId,
+    overrides,
+    workflowName: workflow.name,
+    systemPromptPrefix: getConfig().engine.systemPromptPrefix,
+    systemPromptSuffix: getConfig().engine.systemPromptSuffix,
+    timeout: workflow.timeout ?? getConfig().engine.runTimeoutSeconds,
+    concurrency: getConfig().engine.concurrency,
+    providerRateLimit: getConfig().engine.providerRateLimit,
+    maxUpstreamBytes: getConfig().engine.maxUpstreamBytes,
+    responseCacheTtlMs: getConfig().engine.responseCacheTtlMs,
     onEvent: async (event: ExecutionEvent) => {
+      // Numbered before any await so stored order matches emission order
+      const stored = toStoredEvent(event, eventSeq);
+      if (stored) eventSeq++;
       try {
-        if (event.type === 'node-start' || event.type === 'node-complete' || event.type === 'node-error') {
+        if (stored) {
+          await db.insert(runEvents).values({ runId, ...stored });
+        }
+
+        // Partial fields ride along in node states so the state stream can show them early
+        if (['node-start', 'node-retry', 'node-partial-output', 'node-complete', 'node-error'].includes(event.type)) {
           const state = executor.getState();
           await db
             .update(runs)
@@ -70,7 +293,14 @@ export async function startWorkflowRun(workflowId: string, userId: string): Prom
               nodeStates: state.nodeStates,
               tokenUsage: state.totalTokens,
             })
-            .where(eq(runs.id, run.id));
+            .where(eq(runs.id, runId));
+        }
+
+        if (event.type === 'run-paused' || event.type === 'run-resumed') {
+          await db
+            .update(runs)
+            .set({ status: event.type === 'run-paused' ? 'paused' : 'running' })
+            .where(eq(runs.id, runId));
         }
 
         if (event.type === 'run-complete') {
@@ -84,17 +314,17 @@ export async function startWorkflowRun(workflowId: string, userId: string): Prom
               tokenUsage: data.totalTokens,
               completedAt: new Date(),
             })
-            .where(eq(runs.id, run.id));
+            .where(eq(runs.id, runId));
         }
       } catch (err) {
-        console.error('[run-simple] Failed to update run state:', err);
+        log.error('Failed to update run state', { error: String(err) });
       }
     },
   });
 
   // Fire and forget — execution happens in background
-  executor.execute().catch(async (err) => {
-    console.error('[run-simple] Execution failed:', err);
+  const done = executor.execute().catch(async (err) => {
+    log.error('Execution failed', { error: String(err) });
     try {
       await db
         .update(runs)
@@ -103,9 +333,63 @@ export async function startWorkflowRun(workflowId: string, userId: string): Prom
           context: { error: err instanceof Error ? err.message : String(err) },
           completedAt: new Date(),
         })
-        .where(eq(runs.id, run.id));
+        .where(eq(runs.id, runId));
     } catch { /* DB update failed too */ }
   });
+  // A slot frees up once this run settles and is unregistered
+  void registerActiveRun(runId, executor, done).then(() => dispatchQueuedRuns());
+}
+
+/**
+ * Aborts in-flight runs on server shutdown. Runs that don't stop before the
+ * deadline get their current node states checkpointed and are marked cancelled.
+ */
+export async function shutdownWorkflowRuns(timeoutMs: number): Promise<void> {
+  const interrupted = await drainActiveRuns(timeoutMs);
+
+  await Promise.all(interrupted.map(async ({ runId, executor }) => {
+    const state = executor.getState();
+    try {
+      await db
+        .update(runs)
+        .set({
+          status: 'cancelled',
+          nodeStates: state.nodeStates,
+          tokenUsage: state.totalTokens,
+          context: { error: 'Interrupted by server shutdown' },
+          completedAt: new Date(),
+        })
+        .where(eq(runs.id, runId));
+    } catch (err) {
+      logger.error('Failed to checkpoint interrupted run', { runId, error: String(err) });
+    }
+  }));
 
-  return { runId: run.id, status: 'running' };
+  if (interrupted.length > 0) {
+    logger.warn('Interrupted runs on shutdown', { count: interrupted.length });
+  }
+}
+
+/**
+ * Paused runs only live in the executor that paused them, so after a restart
+ * nothing can resume them. Called on server start to close them out instead
+ * of leaving them paused forever.
+ */
+export async function reconcilePausedRuns(): Promise<void> {
+  try {
+    const stale = await db
+      .update(runs)
+      .set({
+        status: 'cancelled',
+        context: { error: 'Interrupted by server restart while paused' },
+        completedAt: new Date(),
+      })
+      .where(eq(runs.status, 'paused'))
+      .returning({ id: runs.id });
+    if (stale.length > 0) {
+      logger.warn('Cancelled runs left paused by a restart', { count: stale.length });
+    }
+  } catch (err) {
+    logger.error('Failed to reconcile paused runs', { error: String(err) });
+  }
 }
@@ -41,12 +41,24 @@ export class Scheduler {
 
     if (ready.length === 0) return null;
 
+    ready.sort((a, b) => this.compareReady(a, b));
+
     return {
       nodeIds: ready,
       isParallel: ready.length > 1,
     };
   }
 
+  // Descending priority, ties broken by label then id for a stable order
+  private compareReady(a: string, b: string): number {
+    const na = this.graph.getNode(a);
+    const nb = this.graph.getNode(b);
+    const diff = (nb?.data.priority || 0) - (na?.data.priority || 0);
+    if (diff !== 0) return diff;
+    const byLabel = (na?.data.label || '').localeCompare(nb?.data.label || '');
+    return byLabel !== 0 ? byLabel : a.localeCompare(b);
+  }
+
   markCompleted(nodeId: string): void {
     this.completed.add(nodeId);
   }
@@ -0,0 +1,35 @@
+export interface TruncateResult {
+  text: string;
+  truncatedBytes: number;
+}
+
+/**
+ * Caps text at `maxBytes` of UTF-8, keeping the head and tail and marking the
+ * elided middle so downstream agents can tell the output was cut.
+ */
+export function truncateMiddle(text: string, maxBytes: number): TruncateResult {
+  const bytes = Buffer.from(text, 'utf8');
+  if (maxBytes <= 0 || bytes.length <= maxBytes) {
+    return { text, truncatedBytes: 0 };
+  }
+
+  const half = Math.floor(maxBytes / 2);
+  // Cut on character boundaries: shrink the head and tail past any UTF-8 continuation bytes
+  let headEnd = half;
+  while (headEnd > 0 && isContinuationByte(bytes[headEnd])) headEnd--;
+  let tailStart = bytes.length - half;
+  while (tailStart < bytes.length && isContinuationByte(bytes[tailStart])) tailStart++;
+
+  const truncatedBytes = tailStart - headEnd;
+  const head = bytes.subarray(0, headEnd).toString('utf8');
+  const tail = bytes.subarray(tailStart).toString('utf8');
+
+  return {
+    text: `${head}\n[...truncated ${truncatedBytes} bytes...]\n${tail}`,
+    truncatedBytes,
+  };
+}
+
+function isContinuationByte(byte: number): boolean {
+  return (byte & 0xc0) === 0x80;
+}
@@ -9,6 +9,23 @@ export interface RouteDefinition {
   condition?: string;
 }
 
+export type JitterMode = 'none' | 'proportional' | 'full';
+
+export type OutputFieldType = 'string' | 'number' | 'integer' | 'boolean' | 'string[]' | 'number[]' | 'object' | 'object[]';
+
+export interface OutputField {
+  name: string;
+  type: OutputFieldType;
+  required?: boolean;
+  description?: string;
+}
+
+export interface OutputSchema {
+  fields: OutputField[];
+  // Validate the output-manifest block and re-prompt once on violation
+  strict?: boolean;
+}
+
 export interface WorkflowNode {
   id: string;
   type: 'agent' | 'condition' | 'input' | 'output' | 'loop' | 'router' | 'transform' | 'gate';
@@ -21,10 +38,26 @@ export interface WorkflowNode {
     tools?: ToolDefinition[];
     condition?: string;
     retries?: number;
+    retryJitter?: JitterMode;
+    // Retry even after output has streamed; off by default so partial output isn't re-emitted
+    retryAfterOutput?: boolean;
     timeout?: number;
+    // Higher runs first when more nodes are ready than the executor's concurrency
+    priority?: number;
     maxTurns?: number;
+    // Overrides the provider's context window (tokens) for prompt fitting
+    contextWindow?: number;
+    // Reuse the response for identical prompts (ignored when a workspace is used)
+    cacheResponses?: boolean;
+    // Max provider requests per minute from this node, including retries
+    rateLimit?: number;
     workspace?: 'off' | 'safe' | 'full';
     permissionMode?: 'default' | 'accept-edits' | 'full';
+    // Input
+    variableName?: string;
+    defaultValue?: string;
+    required?: boolean;
+    inputType?: 'string' | 'number' | 'boolean' | 'json';
     // Router
     routes?: RouteDefinition[];
     // Transform
@@ -33,6 +66,8 @@ export interface WorkflowNode {
     gateMessage?: string;
     // Provider
     provider?: string;
+    // Structured output
+    outputSchema?: OutputSchema;
   };
 }
 
@@ -52,6 +87,7 @@ export interface Workflow {
   nodes: WorkflowNode[];
   edges: WorkflowEdge[];
   variables: Record<string, string>;
+  timeout?: number | null;
   createdAt: Date;
   updatedAt: Date;
   userId: string;
@@ -74,13 +110,28 @@ export interface NodeRunState {
   error?: string;
   tokens?: { input: number; output: number };
   files?: { path: string; size: number }[];
+  // Output was served from the response cache
+  cached?: boolean;
+  // Structured output fields parsed so far while the response streams
+  partialFields?: Record<string, unknown>;
+  // Provider attempts made, set once a node has been retried
+  attempts?: number;
   startedAt?: Date;
   completedAt?: Date;
 }
 
 export interface ExecutionEvent {
-  type: 'node-start' | 'node-output' | 'node-complete' | 'node-error' | 'node-waiting' | 'run-complete' | 'run-error';
+  type: 'node-start' | 'node-output' | 'node-partial-output' | 'node-retry' | 'node-complete' | 'node-error' | 'node-waiting' | 'run-progress' | 'run-paused' | 'run-resumed' | 'run-complete' | 'run-error';
   nodeId?: string;
   data: unknown;
   timestamp: Date;
 }
+
+// Run-time settings applied to every agent node, taking precedence over node data
+// Temperature, seed and max tokens have no counterpart: the provider CLIs don't accept them
+export interface RunOverrides {
+  provider?: string;
+  // Passed to the provider CLI's --model
+  model?: string;
+  maxTurns?: number;
+}
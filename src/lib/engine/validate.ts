@@ -0,0 +1,78 @@
+import { Graph } from './graph';
+import { workflowGraphSchema } from '../workflow-schema';
+import type { WorkflowEdge, WorkflowNode } from './types';
+import { hasProvider } from '@/lib/providers/registry';
+
+export interface WorkflowSource {
+  name: string;
+  // Where the workflow came from (file path or row id), used in reports
+  source?: string;
+  graphData: unknown;
+}
+
+export interface WorkflowValidationResult {
+  name: string;
+  source?: string;
+  errors: string[];
+}
+
+/**
+ * Checks a workflow's graph shape, node configuration and DAG validity,
+ * returning every problem found rather than stopping at the first.
+ */
+export function validateWorkflow(graphData: unknown): string[] {
+  const parsed = workflowGraphSchema.safeParse(graphData);
+  if (!parsed.success) {
+    return parsed.error.issues.map(issue => {
+      const path = issue.path.join('.');
+      return path ? `${path}: ${issue.message}` : issue.message;
+    });
+  }
+
+  const nodes = parsed.data.nodes as WorkflowNode[];
+  const edges = parsed.data.edges as WorkflowEdge[];
+  const errors: string[] = [];
+
+  const seen = new Set<string>();
+  for (const node of nodes) {
+    if (seen.has(node.id)) errors.push(`Duplicate node id "${node.id}"`);
+    seen.add(node.id);
+
+    if (node.type === 'router' && !node.data.routes?.length) {
+      errors.push(`Router node "${node.data.label}" must have at least one route`);
+    }
+    if (node.type === 'transform' && !node.data.template) {
+      errors.push(`Transform node "${node.data.label}" must have a template`);
+    }
+  }
+
+  errors.push(...new Graph(nodes, edges).validate().errors);
+  return errors;
+}
+
+export function validateWorkflows(workflows: WorkflowSource[]): WorkflowValidationResult[] {
+  return workflows.map(w => ({
+    name: w.name,
+    source: w.source,
+    errors: validateWorkflow(w.graphData),
+  }));
+}
+
+/**
+ * Lists nodes that name a provider not in the registry, built in or added
+ * with registerProvider. Checked when a workflow is saved, since at run time
+ * the executor silently falls back to claude-code.
+ */
+export function findUnknownProviders(graphData: unknown): string[] {
+  const nodes = (graphData as { nodes?: unknown } | null | undefined)?.nodes;
+  if (!Array.isArray(nodes)) return [];
+
+  const errors: string[] = [];
+  for (const node of nodes as Partial<WorkflowNode>[]) {
+    const provider = node?.data?.provider;
+    if (provider !== undefined && !hasProvider(provider)) {
+      errors.push(`Node "${node.data?.label || node.id}" uses unknown provider "${provider}"`);
+    }
+  }
+  return errors;
+}
@@ -0,0 +1,44 @@
+const ttlMs = 10 * 60_000; // keys are honoured for 10 minutes
+
+interface Entry {
+  result: Promise<unknown>;
+  expiresAt: number;
+}
+
+const store = new Map<string, Entry>();
+
+// Cleanup expired keys every 5 minutes
+const cleanupInterval = setInterval(() => {
+  const now = Date.now();
+  for (const [key, entry] of store) {
+    if (entry.expiresAt <= now) store.delete(key);
+  }
+}, 300_000);
+cleanupInterval.unref();
+
+/**
+ * Runs `fn` once per key within the TTL. Repeat calls with the same key get
+ * the first call's result, including while it is still in flight. Failed
+ * calls are forgotten so the client can retry.
+ */
+export async function withIdempotency<T>(
+  key: string,
+  fn: () => Promise<T>
+): Promise<{ value: T; replayed: boolean }> {
+  const now = Date.now();
+  const existing = store.get(key);
+  if (existing && existing.expiresAt > now) {
+    return { value: (await existing.result) as T, replayed: true };
+  }
+
+  const result = fn();
+  const entry: Entry = { result, expiresAt: now + ttlMs };
+  store.set(key, entry);
+
+  try {
+    return { value: await result, replayed: false };
+  } catch (error) {
+    if (store.get(key) === entry) store.delete(key);
+    throw error;
+  }
+}
@@ -51,24 +51,44 @@ function formatMessage(level: LogLevel, message: string, context?: LogContext):
   return `${prefix} ${message}`;
 }
 
-export const logger = {
-  debug(message: string, context?: LogContext): void {
-    if (!shouldLog('debug')) return;
-    console.debug(formatMessage('debug', message, context));
-  },
-
-  info(message: string, context?: LogContext): void {
-    if (!shouldLog('info')) return;
-    console.info(formatMessage('info', message, context));
-  },
-
-  warn(message: string, context?: LogContext): void {
-    if (!shouldLog('warn')) return;
-    console.warn(formatMessage('warn', message, context));
-  },
-
-  error(message: string, context?: LogContext): void {
-    if (!shouldLog('error')) return;
-    console.error(formatMessage('error', message, context));
-  },
-};
+export interface Logger {
+  debug(message: string, context?: LogContext): void;
+  info(message: string, context?: LogContext): void;
+  warn(message: string, context?: LogContext): void;
+  error(message: string, context?: LogContext): void;
+  /** Returns a logger that adds `fields` (e.g. runId, nodeId) to every line. */
+  child(fields: LogContext): Logger;
+}
+
+function createLogger(fields: LogContext): Logger {
+  const merge = (context?: LogContext): LogContext | undefined =>
+    Object.keys(fields).length > 0 ? { ...fields, ...context } : context;
+
+  return {
+    debug(message, context) {
+      if (!shouldLog('debug')) return;
+      console.debug(formatMessage('debug', message, merge(context)));
+    },
+
+    info(message, context) {
+      if (!shouldLog('info')) return;
+      console.info(formatMessage('info', message, merge(context)));
+    },
+
+    warn(message, context) {
+      if (!shouldLog('warn')) return;
+      console.warn(formatMessage('warn', message, merge(context)));
+    },
+
+    error(message, context) {
+      if (!shouldLog('error')) return;
+      console.error(formatMessage('error', message, merge(context)));
+    },
+
+    child(extra) {
+      return createLogger({ ...fields, ...extra });
+    },
+  };
+}
+
+export const logger = createLogger({});
@@ -0,0 +1,28 @@
+import { existsSync, realpathSync } from 'fs';
+import { dirname, isAbsolute, relative, resolve } from 'path';
+
+function isInside(root: string, target: string): boolean {
+  const rel = relative(root, target);
+  return rel === '' || (!rel.startsWith('..') && !isAbsolute(rel));
+}
+
+/**
+ * Resolves `path` against `root` and returns it only if it stays inside the
+ * root, following symlinks. Returns null for `..` escapes, absolute paths
+ * elsewhere and links pointing out of the root. For paths that don't exist
+ * yet, the nearest existing parent is checked instead.
+ */
+export function resolveWithin(root: string, path: string): string | null {
+  const base = resolve(root);
+  const target = resolve(base, path);
+  if (!isInside(base, target)) return null;
+  if (!existsSync(base)) return target;
+
+  let existing = target;
+  while (!existsSync(existing)) existing = dirname(existing);
+  try {
+    return isInside(realpathSync(base), realpathSync(existing)) ? target : null;
+  } catch {
+    return null;
+  }
+}
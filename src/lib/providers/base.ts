@@ -5,4 +5,6 @@ export interface CodingAgentProvider {
   readonly name: string;
   stream(messages: ProviderMessage[], options: ProviderOptions, apiKey: string): AsyncGenerator<StreamChunk>;
   validateCli(): Promise<boolean>;
+  // Exact prompt token count, for providers whose backend exposes a tokenizer
+  countTokens?(messages: ProviderMessage[], options: ProviderOptions): Promise<number>;
 }
@@ -0,0 +1,24 @@
+import { ClaudeCodeProvider } from './claude-code';
+import { getConfig } from '@/lib/config';
+
+/**
+ * Claude Code routed through AWS Bedrock. Credentials come from the standard
+ * AWS chain (env vars, shared config, instance role); only the region is set here.
+ */
+export class BedrockProvider extends ClaudeCodeProvider {
+  readonly id: string = 'bedrock';
+  readonly name: string = 'Claude (Bedrock)';
+
+  // Defaults to AWS_REGION from config, read per run rather than at registration
+  constructor(private readonly region?: string) {
+    super();
+  }
+
+  protected buildEnv(): NodeJS.ProcessEnv {
+    return {
+      ...process.env,
+      CLAUDE_CODE_USE_BEDROCK: '1',
+      AWS_REGION: this.region ?? getConfig().engine.bedrockRegion,
+    };
+  }
+}
@@ -1,10 +1,12 @@
 import { spawn, type ChildProcessWithoutNullStreams } from 'child_process';
 import type { CodingAgentProvider } from './base';
+import { ProviderError } from './errors';
+import { killProcessGroup, ownProcessGroup } from './process';
 import type { ProviderMessage, ProviderOptions, StreamChunk } from '@/types/provider';
 
 export class ClaudeCodeProvider implements CodingAgentProvider {
-  readonly id = 'claude-code';
-  readonly name = 'Claude Code';
+  readonly id: string = 'claude-code';
+  readonly name: string = 'Claude Code';
 
   // eslint-disable-next-line @typescript-eslint/no-unused-vars
   async *stream(messages: ProviderMessage[], options: ProviderOptions, _apiKey: string): AsyncGenerator<StreamChunk> {
@@ -16,12 +18,17 @@ export class ClaudeCodeProvider implements CodingAgentProvider {
     const maxTurns = options.maxTurns || 10;
     args.push('--max-turns', String(maxTurns));
 
+    if (options.model) {
+      args.push('--model', options.model);
+    }
+
     if (workspaceEnabled && options.workspace === 'full') {
       args.push('--dangerously-skip-permissions');
     }
 
-    const spawnOptions: { env: NodeJS.ProcessEnv; cwd?: string } = {
-      env: process.env,
+    const spawnOptions: { env: NodeJS.ProcessEnv; cwd?: string; detached: boolean } = {
+      env: this.buildEnv(),
+      detached: ownProcessGroup,
     };
 
     if (workspaceEnabled) {
@@ -41,33 +48,40 @@ export class ClaudeCodeProvider implements CodingAgentProvider {
     proc.stdout.on('data', (data: Buffer) => { stdout += data.toString(); });
     proc.stderr.on('data', (data: Buffer) => { stderr += data.toString(); });
 
+    let spawnError: NodeJS.ErrnoException | undefined;
     const closePromise = new Promise<{ code: number | null; signal: NodeJS.Signals | null }>((resolve) => {
       proc.on('close', (code, signal) => resolve({ code, signal }));
-    });
-
-    proc.on('error', (err) => {
-      cleanup();
-      if ((err as NodeJS.ErrnoException).code === 'ENOENT') {
-        throw new Error('Claude Code CLI not found. Install it with: npm install -g @anthropic-ai/claude-code');
-      }
-      throw err;
+      proc.on('error', (err) => {
+        spawnError = err;
+        resolve({ code: null, signal: null });
+      });
     });
 
     const result = await closePromise;
     cleanup();
 
+    if (spawnError) {
+      if (spawnError.code === 'ENOENT') {
+        throw new ProviderError(this.id, 'not-installed', 'Claude Code CLI not found. Install it with: npm install -g @anthropic-ai/claude-code');
+      }
+      throw new ProviderError(this.id, 'spawn', spawnError.message, { cause: spawnError });
+    }
+
     if (result.signal === 'SIGTERM' || options.signal?.aborted) {
       if (stdout.trim()) {
         yield { type: 'text', content: this.parseOutput(stdout) };
       }
-      throw new Error('Claude Code was cancelled');
+      throw new ProviderError(this.id, 'cancelled', 'Claude Code was cancelled');
     }
 
     if (result.code !== 0) {
       if (stdout.trim()) {
         yield { type: 'text', content: this.parseOutput(stdout) };
       }
-      throw new Error(stderr || `Claude Code exited with code ${result.code}`);
+      throw new ProviderError(this.id, 'exit', stderr || `Claude Code exited with code ${result.code}`, {
+        exitCode: result.code,
+        stderr,
+      });
     }
 
     const parsed = this.parseOutput(stdout);
@@ -77,12 +91,17 @@ export class ClaudeCodeProvider implements CodingAgentProvider {
 
   async validateCli(): Promise<boolean> {
     return new Promise((resolve) => {
-      const proc = spawn('claude', ['--version']);
+      const proc = spawn('claude', ['--version'], { timeout: 5000 });
       proc.on('close', (code) => resolve(code === 0));
       proc.on('error', () => resolve(false));
     });
   }
 
+  // Environment for the claude process; subclasses point the CLI at other backends
+  protected buildEnv(): NodeJS.ProcessEnv {
+    return process.env;
+  }
+
   private buildPrompt(messages: ProviderMessage[]): string {
     const system = messages.find(m => m.role === 'system')?.content;
     const user = messages
@@ -119,11 +138,11 @@ export class ClaudeCodeProvider implements CodingAgentProvider {
     if (!signal) return () => {};
 
     if (signal.aborted) {
-      proc.kill('SIGTERM');
+      killProcessGroup(proc);
       return () => {};
     }
 
-    const onAbort = () => { proc.kill('SIGTERM'); };
+    const onAbort = () => { killProcessGroup(proc); };
     signal.addEventListener('abort', onAbort, { once: true });
 
     return () => {
@@ -1,5 +1,7 @@
 import { spawn, type ChildProcessWithoutNullStreams } from 'child_process';
 import type { CodingAgentProvider } from './base';
+import { ProviderError } from './errors';
+import { killProcessGroup, ownProcessGroup } from './process';
 import type { ProviderMessage, ProviderOptions, StreamChunk } from '@/types/provider';
 
 export class CodexProvider implements CodingAgentProvider {
@@ -20,9 +22,13 @@ export class CodexProvider implements CodingAgentProvider {
       args.push('--cd', options.workspacePath);
     }
 
+    if (options.model) {
+      args.push('--model', options.model);
+    }
+
     args.push(prompt);
 
-    const proc = spawn('codex', args, { env: process.env });
+    const proc = spawn('codex', args, { env: process.env, detached: ownProcessGroup });
 
     const cleanup = this.attachAbortHandler(proc, options.signal);
 
@@ -31,33 +37,40 @@ export class CodexProvider implements CodingAgentProvider {
     proc.stdout.on('data', (data: Buffer) => { stdout += data.toString(); });
     proc.stderr.on('data', (data: Buffer) => { stderr += data.toString(); });
 
+    let spawnError: NodeJS.ErrnoException | undefined;
     const closePromise = new Promise<{ code: number | null; signal: NodeJS.Signals | null }>((resolve) => {
       proc.on('close', (code, signal) => resolve({ code, signal }));
-    });
-
-    proc.on('error', (err) => {
-      cleanup();
-      if ((err as NodeJS.ErrnoException).code === 'ENOENT') {
-        throw new Error('Codex CLI not found. Install it with: npm install -g @openai/codex');
-      }
-      throw err;
+      proc.on('error', (err) => {
+        spawnError = err;
+        resolve({ code: null, signal: null });
+      });
     });
 
     const result = await closePromise;
     cleanup();
 
+    if (spawnError) {
+      if (spawnError.code === 'ENOENT') {
+        throw new ProviderError(this.id, 'not-installed', 'Codex CLI not found. Install it with: npm install -g @openai/codex');
+      }
+      throw new ProviderError(this.id, 'spawn', spawnError.message, { cause: spawnError });
+    }
+
     if (result.signal === 'SIGTERM' || options.signal?.aborted) {
       if (stdout.trim()) {
         yield { type: 'text', content: this.parseOutput(stdout) };
       }
-      throw new Error('Codex was cancelled');
+      throw new ProviderError(this.id, 'cancelled', 'Codex was cancelled');
     }
 
     if (result.code !== 0) {
       if (stdout.trim()) {
         yield { type: 'text', content: this.parseOutput(stdout) };
       }
-      throw new Error(stderr || `Codex exited with code ${result.code}`);
+      throw new ProviderError(this.id, 'exit', stderr || `Codex exited with code ${result.code}`, {
+        exitCode: result.code,
+        stderr,
+      });
     }
 
     const parsed = this.parseOutput(stdout);
@@ -67,7 +80,7 @@ export class CodexProvider implements CodingAgentProvider {
 
   async validateCli(): Promise<boolean> {
     return new Promise((resolve) => {
-      const proc = spawn('codex', ['--version']);
+      const proc = spawn('codex', ['--version'], { timeout: 5000 });
       proc.on('close', (code) => resolve(code === 0));
       proc.on('error', () => resolve(false));
     });
@@ -131,8 +144,8 @@ export class CodexProvider implements CodingAgentProvider {
 
   private attachAbortHandler(proc: ChildProcessWithoutNullStreams, signal?: AbortSignal): () => void {
     if (!signal) return () => {};
-    if (signal.aborted) { proc.kill('SIGTERM'); return () => {}; }
-    const onAbort = () => { proc.kill('SIGTERM'); };
+    if (signal.aborted) { killProcessGroup(proc); return () => {}; }
+    const onAbort = () => { killProcessGroup(proc); };
     signal.addEventListener('abort', onAbort, { once: true });
     return () => { signal.removeEventListener('abort', onAbort); };
   }
@@ -0,0 +1,81 @@
+import type { CodingAgentProvider } from './base';
+import { logger, type Logger } from '@/lib/logger';
+import { maskSecretValues, REDACTED } from '@/lib/secrets';
+import type { ProviderMessage, ProviderOptions, StreamChunk } from '@/types/provider';
+
+// Longest message or response body logged before truncation
+const MAX_LOGGED_CHARS = 4000;
+
+const SECRET_PATTERNS: RegExp[] = [
+  /((?:x-api-key|api[_-]?key|authorization|token|secret|password)["']?\s*[:=]\s*["']?)(?:Bearer\s+)?[^\s"',]+/gi,
+  /\bsk-[A-Za-z0-9_-]{16,}/g,
+  /\bAKIA[0-9A-Z]{16}\b/g,
+];
+
+/** Masks API keys and credential-looking assignments, plus any explicitly known secret values. */
+export function redactSecrets(text: string, known: string[] = []): string {
+  let out = maskSecretValues(text, known);
+  for (const pattern of SECRET_PATTERNS) {
+    out = out.replace(pattern, (match, prefix?: string) =>
+      typeof prefix === 'string' ? `${prefix}${REDACTED}` : REDACTED
+    );
+  }
+  return out;
+}
+
+function clip(text: string): string {
+  return text.length > MAX_LOGGED_CHARS
+    ? `${text.slice(0, MAX_LOGGED_CHARS)}... [${text.length - MAX_LOGGED_CHARS} more chars]`
+    : text;
+}
+
+/**
+ * Wraps a provider to log each request's messages and the streamed response
+ * at debug level, redacted and truncated. Enabled with PROVIDER_DEBUG.
+ */
+export class DebugProvider implements CodingAgentProvider {
+  readonly id: string;
+  readonly name: string;
+  readonly countTokens?: CodingAgentProvider['countTokens'];
+  private log: Logger;
+
+  constructor(private inner: CodingAgentProvider, log: Logger = logger) {
+    this.id = inner.id;
+    this.name = inner.name;
+    this.log = log.child({ provider: inner.id });
+    // Turning on debug logging must not change how prompts are fitted
+    if (inner.countTokens) this.countTokens = (messages, options) => inner.countTokens!(messages, options);
+  }
+
+  async *stream(messages: ProviderMessage[], options: ProviderOptions, apiKey: string): AsyncGenerator<StreamChunk> {
+    const redact = (text: string) => clip(redactSecrets(text, [apiKey, ...(options.secretValues ?? [])]));
+    this.log.debug('Provider request', {
+      messages: messages.map(m => ({ role: m.role, content: redact(m.content) })),
+      maxTurns: options.maxTurns,
+      workspace: options.workspace,
+    });
+
+    const start = Date.now();
+    let output = '';
+    let tokens: StreamChunk['tokens'];
+    try {
+      for await (const chunk of this.inner.stream(messages, options, apiKey)) {
+        if (chunk.type === 'text') output += chunk.content;
+        if (chunk.tokens) tokens = chunk.tokens;
+        yield chunk;
+      }
+    } catch (err) {
+      this.log.debug('Provider error', {
+        error: redact(err instanceof Error ? err.message : String(err)),
+        partialOutput: redact(output),
+        durationMs: Date.now() - start,
+      });
+      throw err;
+    }
+    this.log.debug('Provider response', { output: redact(output), tokens, durationMs: Date.now() - start });
+  }
+
+  validateCli(): Promise<boolean> {
+    return this.inner.validateCli();
+  }
+}
@@ -0,0 +1,35 @@
+export type ProviderErrorKind = 'not-installed' | 'spawn' | 'cancelled' | 'exit' | 'replay-miss';
+
+/**
+ * Typed failure from a provider CLI. Retry decisions key off `kind` and
+ * `exitCode` rather than the message, which varies between CLI versions.
+ */
+export class ProviderError extends Error {
+  readonly provider: string;
+  readonly kind: ProviderErrorKind;
+  readonly exitCode?: number | null;
+  readonly stderr?: string;
+
+  constructor(
+    provider: string,
+    kind: ProviderErrorKind,
+    message: string,
+    details: { exitCode?: number | null; stderr?: string; cause?: unknown } = {}
+  ) {
+    super(message, details.cause !== undefined ? { cause: details.cause } : undefined);
+    this.name = 'ProviderError';
+    this.provider = provider;
+    this.kind = kind;
+    this.exitCode = details.exitCode;
+    this.stderr = details.stderr;
+  }
+
+  // Missing CLIs, cancellations and replay misses won't succeed on a second attempt
+  get retryable(): boolean {
+    return this.kind === 'exit';
+  }
+}
+
+export function isRetryableError(error: unknown): boolean {
+  return error instanceof ProviderError ? error.retryable : true;
+}
@@ -1,5 +1,7 @@
 import { spawn, type ChildProcessWithoutNullStreams } from 'child_process';
 import type { CodingAgentProvider } from './base';
+import { ProviderError } from './errors';
+import { killProcessGroup, ownProcessGroup } from './process';
 import type { ProviderMessage, ProviderOptions, StreamChunk } from '@/types/provider';
 
 export class GeminiProvider implements CodingAgentProvider {
@@ -16,8 +18,13 @@ export class GeminiProvider implements CodingAgentProvider {
       args.push('--yolo');
     }
 
-    const spawnOptions: { env: NodeJS.ProcessEnv; cwd?: string } = {
+    if (options.model) {
+      args.push('--model', options.model);
+    }
+
+    const spawnOptions: { env: NodeJS.ProcessEnv; cwd?: string; detached: boolean } = {
       env: process.env,
+      detached: ownProcessGroup,
     };
 
     if (options.workspacePath) {
@@ -33,26 +40,30 @@ export class GeminiProvider implements CodingAgentProvider {
     proc.stdout.on('data', (data: Buffer) => { stdout += data.toString(); });
     proc.stderr.on('data', (data: Buffer) => { stderr += data.toString(); });
 
+    let spawnError: NodeJS.ErrnoException | undefined;
     const closePromise = new Promise<{ code: number | null; signal: NodeJS.Signals | null }>((resolve) => {
       proc.on('close', (code, signal) => resolve({ code, signal }));
-    });
-
-    proc.on('error', (err) => {
-      cleanup();
-      if ((err as NodeJS.ErrnoException).code === 'ENOENT') {
-        throw new Error('Gemini CLI not found. Install it with: npm install -g @google/gemini-cli');
-      }
-      throw err;
+      proc.on('error', (err) => {
+        spawnError = err;
+        resolve({ code: null, signal: null });
+      });
     });
 
     const result = await closePromise;
     cleanup();
 
+    if (spawnError) {
+      if (spawnError.code === 'ENOENT') {
+        throw new ProviderError(this.id, 'not-installed', 'Gemini CLI not found. Install it with: npm install -g @google/gemini-cli');
+      }
+      throw new ProviderError(this.id, 'spawn', spawnError.message, { cause: spawnError });
+    }
+
     if (result.signal === 'SIGTERM' || options.signal?.aborted) {
       if (stdout.trim()) {
         yield { type: 'text', content: this.parseOutput(stdout) };
       }
-      throw new Error('Gemini was cancelled');
+      throw new ProviderError(this.id, 'cancelled', 'Gemini was cancelled');
     }
 
     // Exit code 53 = turn limit exceeded — still produce output
@@ -60,7 +71,10 @@ export class GeminiProvider implements CodingAgentProvider {
       if (stdout.trim()) {
         yield { type: 'text', content: this.parseOutput(stdout) };
       }
-      throw new Error(stderr || `Gemini exited with code ${result.code}`);
+      throw new ProviderError(this.id, 'exit', stderr || `Gemini exited with code ${result.code}`, {
+        exitCode: result.code,
+        stderr,
+      });
     }
 
     const parsed = this.parseOutput(stdout);
@@ -70,7 +84,7 @@ export class GeminiProvider implements CodingAgentProvider {
 
   async validateCli(): Promise<boolean> {
     return new Promise((resolve) => {
-      const proc = spawn('gemini', ['--version']);
+      const proc = spawn('gemini', ['--version'], { timeout: 5000 });
       proc.on('close', (code) => resolve(code === 0));
       proc.on('error', () => resolve(false));
     });
@@ -127,8 +141,8 @@ export class GeminiProvider implements CodingAgentProvider {
 
   private attachAbortHandler(proc: ChildProcessWithoutNullStreams, signal?: AbortSignal): () => void {
     if (!signal) return () => {};
-    if (signal.aborted) { proc.kill('SIGTERM'); return () => {}; }
-    const onAbort = () => { proc.kill('SIGTERM'); };
+    if (signal.aborted) { killProcessGroup(proc); return () => {}; }
+    const onAbort = () => { killProcessGroup(proc); };
     signal.addEventListener('abort', onAbort, { once: true });
     return () => { signal.removeEventListener('abort', onAbort); };
   }
@@ -0,0 +1,20 @@
+import type { ChildProcess } from 'child_process';
+
+// Provider CLIs run in their own process group so cancelling also stops the tools they spawned
+export const ownProcessGroup = process.platform !== 'win32';
+
+/**
+ * Signals the child's whole process group, falling back to the child alone
+ * when it has no group of its own (Windows, or already exited).
+ */
+export function killProcessGroup(proc: ChildProcess, signal: NodeJS.Signals = 'SIGTERM'): void {
+  if (ownProcessGroup && proc.pid) {
+    try {
+      process.kill(-proc.pid, signal);
+      return;
+    } catch {
+      // Group already gone; fall through
+    }
+  }
+  proc.kill(signal);
+}
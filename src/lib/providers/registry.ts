@@ -2,26 +2,65 @@ import type { CodingAgentProvider } from './base';
 import { ClaudeCodeProvider } from './claude-code';
 import { CodexProvider } from './codex';
 import { GeminiProvider } from './gemini';
+import { BedrockProvider } from './bedrock';
+import { ReplayProvider } from './replay';
+import { DebugProvider } from './debug';
+import { getConfig } from '@/lib/config';
 
 const providers = new Map<string, CodingAgentProvider>();
+const replayed = new Map<string, CodingAgentProvider>();
+const debugged = new Map<string, CodingAgentProvider>();
 
-function register(provider: CodingAgentProvider): void {
+/**
+ * Adds a provider under its id, e.g. from an embedding application at
+ * startup. Throws if the id is taken unless `override` is set.
+ */
+export function registerProvider(provider: CodingAgentProvider, { override = false } = {}): void {
+  if (providers.has(provider.id) && !override) {
+    throw new Error(`Provider "${provider.id}" is already registered`);
+  }
   providers.set(provider.id, provider);
+  // Drop wrappers around a replaced provider
+  replayed.delete(provider.id);
+  debugged.delete(provider.id);
 }
 
-register(new ClaudeCodeProvider());
-register(new CodexProvider());
-register(new GeminiProvider());
+registerProvider(new ClaudeCodeProvider());
+registerProvider(new CodexProvider());
+registerProvider(new GeminiProvider());
+registerProvider(new BedrockProvider());
 
 export function getProvider(id: string): CodingAgentProvider {
-  const provider = providers.get(id);
-  if (!provider) {
-    // Fall back to claude-code for unknown/missing provider IDs
-    return providers.get('claude-code')!;
+  // Fall back to claude-code for unknown/missing provider IDs
+  let provider = providers.get(id) || providers.get('claude-code')!;
+
+  const { replayPath, replayMode, providerDebug } = getConfig().engine;
+  if (replayPath) {
+    let wrapped = replayed.get(provider.id);
+    if (!wrapped) {
+      wrapped = new ReplayProvider(provider, replayPath, replayMode);
+      replayed.set(provider.id, wrapped);
+    }
+    provider = wrapped;
+  }
+
+  if (providerDebug) {
+    let wrapped = debugged.get(provider.id);
+    if (!wrapped) {
+      wrapped = new DebugProvider(provider);
+      debugged.set(provider.id, wrapped);
+    }
+    provider = wrapped;
   }
+
   return provider;
 }
 
+// Whether the id is registered, built in or via registerProvider
+export function hasProvider(id: string): boolean {
+  return providers.has(id);
+}
+
 export function listProviders(): CodingAgentProvider[] {
   return [...providers.values()];
 }
@@ -0,0 +1,102 @@
+import { createHash } from 'crypto';
+import { appendFileSync, existsSync, readFileSync } from 'fs';
+import type { CodingAgentProvider } from './base';
+import { ProviderError } from './errors';
+import type { ProviderMessage, ProviderOptions, StreamChunk } from '@/types/provider';
+
+export type ReplayMode = 'record' | 'replay';
+
+interface Recording {
+  key: string;
+  provider: string;
+  chunks: StreamChunk[];
+}
+
+/**
+ * Stable hash of everything that shapes a provider response. Workspace paths
+ * differ between runs, so only the mode is included.
+ */
+export function requestKey(providerId: string, messages: ProviderMessage[], options: ProviderOptions): string {
+  return createHash('sha256')
+    .update(JSON.stringify({
+      provider: providerId,
+      messages,
+      model: options.model,
+      workspace: options.workspace,
+      maxTurns: options.maxTurns,
+    }))
+    .digest('hex');
+}
+
+/**
+ * Wraps a provider to record each request's streamed chunks to a JSONL file,
+ * or to serve them back from that file without invoking the CLI. Repeated
+ * identical requests replay in recorded order.
+ */
+export class ReplayProvider implements CodingAgentProvider {
+  readonly id: string;
+  readonly name: string;
+  readonly countTokens?: CodingAgentProvider['countTokens'];
+  private recordings: Map<string, StreamChunk[][]> | null = null;
+  private served = new Map<string, number>();
+
+  constructor(
+    private inner: CodingAgentProvider,
+    private path: string,
+    private mode: ReplayMode
+  ) {
+    this.id = inner.id;
+    this.name = inner.name;
+    // Only defined when the inner provider counts, so the heuristic fallback still applies otherwise
+    if (inner.countTokens) this.countTokens = (messages, options) => inner.countTokens!(messages, options);
+  }
+
+  async *stream(messages: ProviderMessage[], options: ProviderOptions, apiKey: string): AsyncGenerator<StreamChunk> {
+    const key = requestKey(this.id, messages, options);
+
+    if (this.mode === 'replay') {
+      yield* this.replay(key);
+      return;
+    }
+
+    const chunks: StreamChunk[] = [];
+    for await (const chunk of this.inner.stream(messages, options, apiKey)) {
+      chunks.push(chunk);
+      yield chunk;
+    }
+    const recording: Recording = { key, provider: this.id, chunks };
+    appendFileSync(this.path, JSON.stringify(recording) + '\n', 'utf-8');
+  }
+
+  validateCli(): Promise<boolean> {
+    return this.mode === 'replay' ? Promise.resolve(true) : this.inner.validateCli();
+  }
+
+  private *replay(key: string): Generator<StreamChunk> {
+    const entries = this.load().get(key);
+    if (!entries?.length) {
+      throw new ProviderError(this.id, 'replay-miss', `No recorded response for this ${this.name} request in ${this.path}`);
+    }
+    const index = this.served.get(key) || 0;
+    this.served.set(key, index + 1);
+    yield* entries[Math.min(index, entries.length - 1)];
+  }
+
+  private load(): Map<string, StreamChunk[][]> {
+    if (this.recordings) return this.recordings;
+
+    const recordings = new Map<string, StreamChunk[][]>();
+    if (existsSync(this.path)) {
+      for (const line of readFileSync(this.path, 'utf-8').split('\n')) {
+        if (!line.trim()) continue;
+        const entry = JSON.parse(line) as Recording;
+        if (entry.provider !== this.id) continue;
+        const list = recordings.get(entry.key) || [];
+        list.push(entry.chunks);
+        recordings.set(entry.key, list);
+      }
+    }
+    this.recordings = recordings;
+    return recordings;
+  }
+}
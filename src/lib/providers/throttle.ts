@@ -0,0 +1,58 @@
+/**
+ * Spaces provider calls to at most `perMinute` per key. Holds one token, so
+ * bursts are smoothed out rather than allowed up front.
+ */
+export class TokenBucket {
+  private tokens = 1;
+  private updatedAt: number;
+
+  constructor(readonly perMinute: number, private readonly now: () => number = Date.now) {
+    this.updatedAt = now();
+  }
+
+  async acquire(signal?: AbortSignal): Promise<void> {
+    const intervalMs = 60_000 / this.perMinute;
+    for (;;) {
+      if (signal?.aborted) throw new Error('Aborted while waiting for provider rate limit');
+
+      const now = this.now();
+      this.tokens = Math.min(1, this.tokens + (now - this.updatedAt) / intervalMs);
+      this.updatedAt = now;
+
+      if (this.tokens >= 1) {
+        this.tokens -= 1;
+        return;
+      }
+
+      const waitMs = Math.ceil((1 - this.tokens) * intervalMs);
+      await new Promise<void>((resolve) => {
+        const timer = setTimeout(done, waitMs);
+        function done() {
+          clearTimeout(timer);
+          signal?.removeEventListener('abort', done);
+          resolve();
+        }
+        signal?.addEventListener('abort', done, { once: true });
+      });
+    }
+  }
+}
+
+const buckets = new Map<string, TokenBucket>();
+
+/** Waits for a slot under `key`; a limit of 0 or less disables throttling. */
+export async function acquireRateLimit(key: string, perMinute: number | undefined, signal?: AbortSignal): Promise<void> {
+  if (!perMinute || perMinute <= 0) return;
+
+  let bucket = buckets.get(key);
+  if (!bucket || bucket.perMinute !== perMinute) {
+    bucket = new TokenBucket(perMinute);
+    buckets.set(key, bucket);
+  }
+  await bucket.acquire(signal);
+}
+
+// Test helper
+export function resetRateLimits(): void {
+  buckets.clear();
+}
@@ -0,0 +1,62 @@
+export type ReadinessCheck = () => Promise<void>;
+
+export interface CheckResult {
+  status: 'ok' | 'error';
+  error?: string;
+}
+
+export interface ReadinessReport {
+  ready: boolean;
+  checks: Record<string, CheckResult>;
+}
+
+/**
+ * Runs dependency checks concurrently; a check passes by resolving. Each
+ * one gets `timeoutMs` so a hung dependency fails the probe instead of
+ * stalling it.
+ */
+export async function runReadinessChecks(
+  checks: Record<string, ReadinessCheck>,
+  timeoutMs = 3000
+): Promise<ReadinessReport> {
+  const entries = await Promise.all(Object.entries(checks).map(async ([name, check]) => {
+    let timer: ReturnType<typeof setTimeout> | undefined;
+    try {
+      await Promise.race([
+        check(),
+        new Promise<never>((_, reject) => {
+          timer = setTimeout(() => reject(new Error(`Timed out after ${timeoutMs}ms`)), timeoutMs);
+        }),
+      ]);
+      return [name, { status: 'ok' }] as const;
+    } catch (error) {
+      return [name, { status: 'error', error: error instanceof Error ? error.message : String(error) }] as const;
+    } finally {
+      clearTimeout(timer);
+    }
+  }));
+
+  const results: Record<string, CheckResult> = Object.fromEntries(entries);
+  return { ready: Object.values(results).every(r => r.status === 'ok'), checks: results };
+}
+
+/**
+ * Wraps a check so its outcome is reused for `ttlMs`, and concurrent probes
+ * share one in-flight run. For checks that are too costly to repeat on
+ * every probe, like spawning the provider CLIs.
+ */
+export function cachedCheck(check: ReadinessCheck, ttlMs = 60_000, now = Date.now): ReadinessCheck {
+  let cached: { result: Promise<void>; expiresAt: number } | undefined;
+  return () => {
+    if (!cached || now() >= cached.expiresAt) {
+      const entry = { result: check(), expiresAt: Infinity };
+      // The TTL starts once the check settles, so a slow run isn't repeated while pending
+      entry.result.then(
+        () => { entry.expiresAt = now() + ttlMs; },
+        () => { entry.expiresAt = now() + ttlMs; }
+      );
+      cached = entry;
+    }
+    return cached.result;
+  };
+}
@@ -0,0 +1,10 @@
+import { sql, type SQL } from 'drizzle-orm';
+import { runs } from '@/lib/db/schema';
+
+/**
+ * Matches runs carrying label key=value. Written as jsonb containment so the
+ * GIN index on runs.labels can serve it.
+ */
+export function runLabelCondition(label: { key: string; value: string }): SQL {
+  return sql`${runs.labels} @> ${JSON.stringify({ [label.key]: label.value })}::jsonb`;
+}
@@ -0,0 +1,87 @@
+import { readFileSync } from 'fs';
+import { isAbsolute, relative, resolve } from 'path';
+
+// secret://env/NAME or secret://file/relative/path
+const SECRET_REF = /^secret:\/\/(env|file)\/(.+)$/;
+
+// Only env vars with this prefix can be referenced, so workflows can't read server credentials
+export const SECRET_ENV_PREFIX = 'CADRE_SECRET_';
+
+export class SecretError extends Error {
+  constructor(message: string) {
+    super(message);
+    this.name = 'SecretError';
+  }
+}
+
+export interface SecretSource {
+  // Directory file references resolve within; file references are rejected when empty
+  secretsDir: string;
+  env?: NodeJS.ProcessEnv;
+}
+
+export function isSecretRef(value: unknown): value is string {
+  return typeof value === 'string' && SECRET_REF.test(value);
+}
+
+export function resolveSecret(ref: string, source: SecretSource): string {
+  const match = ref.match(SECRET_REF);
+  if (!match) throw new SecretError(`Not a secret reference: ${ref}`);
+  const [, scheme, target] = match;
+
+  if (scheme === 'env') {
+    if (!target.startsWith(SECRET_ENV_PREFIX)) {
+      throw new SecretError(`${ref}: env secrets must be named ${SECRET_ENV_PREFIX}*`);
+    }
+    const value = (source.env ?? process.env)[target];
+    if (!value) throw new SecretError(`${ref}: environment variable is not set`);
+    return value;
+  }
+
+  if (!source.secretsDir) {
+    throw new SecretError(`${ref}: file secrets are disabled (SECRETS_DIR is not set)`);
+  }
+  const root = resolve(source.secretsDir);
+  const path = resolve(root, target);
+  const rel = relative(root, path);
+  if (!rel || rel.startsWith('..') || isAbsolute(rel)) {
+    throw new SecretError(`${ref}: path is outside the secrets directory`);
+  }
+  try {
+    return readFileSync(path, 'utf-8').trim();
+  } catch {
+    throw new SecretError(`${ref}: file could not be read`);
+  }
+}
+
+/**
+ * Splits workflow variables into plain values and resolved secrets. Secrets
+ * are kept apart so they never enter the run context, which is persisted and
+ * shown in reports.
+ */
+export function resolveSecretVariables(
+  variables: Record<string, string>,
+  source: SecretSource
+): { variables: Record<string, string>; secrets: Record<string, string> } {
+  const plain: Record<string, string> = {};
+  const secrets: Record<string, string> = {};
+  for (const [key, value] of Object.entries(variables)) {
+    if (isSecretRef(value)) {
+      secrets[key] = resolveSecret(value, source);
+    } else {
+      plain[key] = value;
+    }
+  }
+  return { variables: plain, secrets };
+}
+
+export const REDACTED = '[REDACTED]';
+
+/** Replaces every occurrence of the given secret values in text. */
+export function maskSecretValues(text: string, values: string[]): string {
+  let out = text;
+  for (const value of values) {
+    if (value) out = out.split(value).join(REDACTED);
+  }
+  return out;
+}
@@ -0,0 +1,89 @@
+export const STREAM_EVENT_TYPES = ['heartbeat', 'state', 'done', 'error'] as const;
+
+export type StreamEventType = (typeof STREAM_EVENT_TYPES)[number];
+
+/**
+ * Parses `?types=` from comma-separated and/or repeated params. Returns null
+ * when no filter was given (send everything) and lists unknown types so the
+ * caller can reject the request.
+ */
+export function parseEventTypeFilter(
+  params: URLSearchParams
+): { types: Set<StreamEventType> | null; unknown: string[] } {
+  const requested = params
+    .getAll('types')
+    .flatMap(v => v.split(','))
+    .map(v => v.trim())
+    .filter(Boolean);
+
+  if (requested.length === 0) return { types: null, unknown: [] };
+
+  const known = new Set<string>(STREAM_EVENT_TYPES);
+  const unknown = requested.filter(t => !known.has(t));
+  return { types: new Set(requested.filter(t => known.has(t)) as StreamEventType[]), unknown };
+}
+
+export interface SseWriter<T extends string = StreamEventType> {
+  /** Writes one event; returns false once the client has gone away. */
+  send(event: T, data: unknown): boolean;
+  close(): void;
+  readonly closed: boolean;
+}
+
+/**
+ * Wraps a stream controller so a failed write (client disconnected) marks the
+ * writer closed instead of being silently retried on every poll. When
+ * keepaliveMs is set, a `: keepalive` comment is written after that long
+ * without output so idle connections aren't dropped by proxies.
+ */
+export function createSseWriter<T extends string = StreamEventType>(
+  controller: Pick<ReadableStreamDefaultController<Uint8Array>, 'enqueue' | 'close'>,
+  types: Set<T> | null,
+  keepaliveMs = 0
+): SseWriter<T> {
+  const encoder = new TextEncoder();
+  let closed = false;
+  let idleTimer: ReturnType<typeof setTimeout> | undefined;
+
+  function scheduleKeepalive() {
+    if (idleTimer) clearTimeout(idleTimer);
+    if (keepaliveMs <= 0 || closed) return;
+    idleTimer = setTimeout(() => write(': keepalive\n\n'), keepaliveMs);
+  }
+
+  function write(frame: string): boolean {
+    if (closed) return false;
+    try {
+      controller.enqueue(encoder.encode(frame));
+      scheduleKeepalive();
+      return true;
+    } catch {
+      closed = true;
+      if (idleTimer) clearTimeout(idleTimer);
+      return false;
+    }
+  }
+
+  scheduleKeepalive();
+
+  return {
+    send(event, data) {
+      if (closed) return false;
+      if (types && !types.has(event)) return true;
+      return write(`event: ${event}\ndata: ${JSON.stringify(data)}\n\n`);
+    },
+    close() {
+      if (closed) return;
+      closed = true;
+      if (idleTimer) clearTimeout(idleTimer);
+      try {
+        controller.close();
+      } catch {
+        // Already closed by the client
+      }
+    },
+    get closed() {
+      return closed;
+    },
+  };
+}
@@ -1,6 +1,7 @@
 import { z } from 'zod/v4';
 import { NextResponse } from 'next/server';
 import { apiError } from './api-error';
+import { hasProvider } from '@/lib/providers/registry';
 
 // --- Common primitives ---
 
@@ -13,7 +14,9 @@ export const paginationSchema = z.object({
 
 export const runStatusSchema = z.enum([
   'pending',
+  'queued',
   'running',
+  'paused',
   'completed',
   'failed',
   'cancelled',
@@ -29,6 +32,13 @@ const graphDataSchema = z
     'Graph data too large (max 5MB)'
   );
 
+const runTimeoutSchema = z
+  .number()
+  .int()
+  .min(10, 'Timeout must be at least 10 seconds')
+  .max(86400, 'Timeout must be at most 24 hours')
+  .nullable();
+
 export const createWorkflowSchema = z.object({
   name: z
     .string()
@@ -42,6 +52,30 @@ export const createWorkflowSchema = z.object({
     .default(''),
   graphData: graphDataSchema,
   variables: z.record(z.string(), z.string()).optional().default({}),
+  timeout: runTimeoutSchema.optional(),
+});
+
+export const importWorkflowsSchema = z.object({
+  // Items are validated one by one so a bad entry doesn't reject the batch
+  workflows: z.array(z.unknown()).min(1, 'Nothing to import').max(100, 'Import at most 100 workflows at a time'),
+  // Import even when names collide with existing workflows or each other
+  force: z.boolean().default(false),
+});
+
+export const cloneWorkflowSchema = z.object({
+  // Defaults to "<source name> (Copy)"
+  name: z
+    .string()
+    .min(1, 'Workflow name cannot be empty')
+    .max(200, 'Workflow name must be under 200 characters')
+    .transform((s) => s.trim())
+    .optional(),
+  description: z
+    .string()
+    .max(5000, 'Description must be under 5000 characters')
+    .transform((s) => s.trim())
+    .optional(),
+  variables: z.record(z.string(), z.string()).optional(),
 });
 
 export const updateWorkflowSchema = z.object({
@@ -58,19 +92,78 @@ export const updateWorkflowSchema = z.object({
     .optional(),
   graphData: graphDataSchema,
   variables: z.record(z.string(), z.string()).optional(),
+  timeout: runTimeoutSchema.optional(),
 });
 
 // --- Run schemas ---
 
+export const idempotencyKeySchema = z.string().min(1).max(255);
+
+const labelKeySchema = z
+  .string()
+  .regex(/^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$/, 'Label keys must be 1-63 letters, digits, "_", "." or "-"');
+
+export const runLabelsSchema = z
+  .record(labelKeySchema, z.string().max(255, 'Label values must be under 255 characters'))
+  .refine((labels) => Object.keys(labels).length <= 20, 'At most 20 labels per run');
+
+export const runOverridesSchema = z.strictObject({
+  provider: z.string().refine(hasProvider, 'Unknown provider').optional(),
+  // Starts alphanumeric so it can't be read as a CLI flag
+  model: z.string().max(100).regex(/^[A-Za-z0-9][\w.:/-]*$/, 'Invalid model name').optional(),
+  maxTurns: z.number().int().min(1).max(50).optional(),
+});
+
+export const runInputsSchema = z
+  .record(z.string().min(1).max(100), z.string().max(100_000, 'Input values must be under 100KB'))
+  .refine((inputs) => Object.keys(inputs).length <= 50, 'At most 50 inputs per run');
+
+export const startRunSchema = z.object({
+  idempotencyKey: idempotencyKeySchema.optional(),
+  // Values for the workflow's input nodes, taking precedence over workflow variables
+  inputs: runInputsSchema.optional(),
+  labels: runLabelsSchema.optional(),
+  overrides: runOverridesSchema.optional(),
+  // Queue position when the server is at capacity; higher starts first
+  priority: z.number().int().min(-100).max(100).optional(),
+});
+
 export const listRunsQuerySchema = paginationSchema.extend({
   workflowId: uuidSchema.optional(),
   status: runStatusSchema.optional(),
+  // label=key=value matches runs carrying that label
+  label: z
+    .string()
+    .regex(/^[^=]+=/, 'Label filter must be key=value')
+    .transform((s) => {
+      const i = s.indexOf('=');
+      return { key: s.slice(0, i), value: s.slice(i + 1) };
+    })
+    .optional(),
 });
 
 export const cancelRunSchema = z.object({
   action: z.literal('cancel'),
 });
 
+export const runActionSchema = z.object({
+  action: z.enum(['cancel', 'pause', 'resume']),
+});
+
+export const runReportQuerySchema = z.object({
+  format: z.enum(['md', 'json']).default('json'),
+});
+
+export const runEventsQuerySchema = z.object({
+  // Stream stored events, then new ones until the run finishes
+  follow: z.enum(['true', 'false']).default('false').transform((v) => v === 'true'),
+});
+
+export const compareRunsQuerySchema = z.object({
+  a: uuidSchema,
+  b: uuidSchema,
+});
+
 // --- Parse helpers ---
 
 function formatZodErrors(error: z.ZodError): Record<string, string[]> {
@@ -0,0 +1,72 @@
+import type { z } from 'zod/v4';
+import { createWorkflowSchema } from './validation';
+import { validateWorkflow } from './engine/validate';
+
+export type ImportCandidate = z.output<typeof createWorkflowSchema>;
+
+export interface ImportItemResult {
+  index: number;
+  name?: string;
+  id?: string;
+  errors?: string[];
+}
+
+/**
+ * Validates each item of a bulk import independently. Returns the items that
+ * can be inserted plus a result per item; failed items carry their errors.
+ */
+export function checkImportItems(items: unknown[]): {
+  valid: { index: number; data: ImportCandidate }[];
+  results: ImportItemResult[];
+} {
+  const valid: { index: number; data: ImportCandidate }[] = [];
+  const results: ImportItemResult[] = [];
+
+  items.forEach((item, index) => {
+    const parsed = createWorkflowSchema.safeParse(item);
+    if (!parsed.success) {
+      results.push({
+        index,
+        name: nameOf(item),
+        errors: parsed.error.issues.map(issue => {
+          const path = issue.path.join('.');
+          return path ? `${path}: ${issue.message}` : issue.message;
+        }),
+      });
+      return;
+    }
+
+    const graphErrors = parsed.data.graphData ? validateWorkflow(parsed.data.graphData) : [];
+    if (graphErrors.length) {
+      results.push({ index, name: parsed.data.name, errors: graphErrors });
+      return;
+    }
+
+    valid.push({ index, data: parsed.data });
+    results.push({ index, name: parsed.data.name });
+  });
+
+  return { valid, results };
+}
+
+function nameOf(item: unknown): string | undefined {
+  if (item && typeof item === 'object' && typeof (item as { name?: unknown }).name === 'string') {
+    return (item as { name: string }).name;
+  }
+  return undefined;
+}
+
+/**
+ * Names in the import that already belong to a workflow, or appear more than
+ * once in the import itself. Sorted, each listed once.
+ */
+export function findNameConflicts(names: string[], existing: Iterable<string>): string[] {
+  const taken = new Set(existing);
+  const seen = new Set<string>();
+  const conflicts = new Set<string>();
+  for (const name of names) {
+    if (taken.has(name) || seen.has(name)) conflicts.add(name);
+    seen.add(name);
+  }
+  return [...conflicts].sort();
+}
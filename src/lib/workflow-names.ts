@@ -0,0 +1,14 @@
+/**
+ * First free copy name for `source`: "X (Copy)", then "X (Copy 2)", "X (Copy 3)"...
+ */
+export function nextCopyName(source: string, taken: Iterable<string>): string {
+  const names = new Set(taken);
+  let name = `${source} (Copy)`;
+  for (let n = 2; names.has(name); n++) name = `${source} (Copy ${n})`;
+  return name;
+}
+
+/** LIKE pattern matching every name nextCopyName could produce for `source`. */
+export function copyNamePattern(source: string): string {
+  return `${source.replace(/[%_\\]/g, '\\$&')} (Copy%`;
+}
@@ -0,0 +1,112 @@
+import { z } from 'zod/v4';
+import { hasProvider, listProviders } from '@/lib/providers/registry';
+
+// Zod mirrors of the engine graph types in '@/lib/engine/types', used to
+// publish JSON Schemas for editor tooling and external workflow authoring.
+
+export const NODE_TYPES = ['agent', 'condition', 'input', 'output', 'loop', 'router', 'transform', 'gate'] as const;
+export const WORKSPACE_MODES = ['off', 'safe', 'full'] as const;
+export const PERMISSION_MODES = ['default', 'accept-edits', 'full'] as const;
+export const INPUT_TYPES = ['string', 'number', 'boolean', 'json'] as const;
+export const JITTER_MODES = ['none', 'proportional', 'full'] as const;
+export const OUTPUT_FIELD_TYPES = ['string', 'number', 'integer', 'boolean', 'string[]', 'number[]', 'object', 'object[]'] as const;
+
+// Checked against the registry at parse time so providers added with registerProvider are accepted
+const providerIdSchema = z.string().refine(hasProvider, 'Unknown provider');
+
+export const outputSchemaSchema = z.object({
+  fields: z.array(z.object({
+    name: z.string().min(1),
+    type: z.enum(OUTPUT_FIELD_TYPES),
+    required: z.boolean().optional(),
+    description: z.string().optional(),
+  })),
+  strict: z.boolean().optional(),
+});
+
+export const nodeDataSchema = z.looseObject({
+  label: z.string().min(1),
+  systemPrompt: z.string().optional(),
+  temperature: z.number().min(0).max(2).optional(),
+  maxTokens: z.number().int().positive().optional(),
+  condition: z.string().optional(),
+  retries: z.number().int().min(0).max(5).optional(),
+  retryJitter: z.enum(JITTER_MODES).optional(),
+  retryAfterOutput: z.boolean().optional(),
+  timeout: z.number().int().min(5).max(3600).optional(),
+  priority: z.number().int().optional(),
+  maxTurns: z.number().int().min(1).max(50).optional(),
+  contextWindow: z.number().int().positive().optional(),
+  cacheResponses: z.boolean().optional(),
+  rateLimit: z.number().int().min(0).optional(),
+  workspace: z.enum(WORKSPACE_MODES).optional(),
+  permissionMode: z.enum(PERMISSION_MODES).optional(),
+  variableName: z.string().optional(),
+  defaultValue: z.string().optional(),
+  required: z.boolean().optional(),
+  inputType: z.enum(INPUT_TYPES).optional(),
+  routes: z.array(z.object({
+    label: z.string(),
+    condition: z.string().optional(),
+  })).optional(),
+  template: z.string().optional(),
+  gateMessage: z.string().optional(),
+  provider: providerIdSchema.optional(),
+  outputSchema: outputSchemaSchema.optional(),
+});
+
+export const workflowNodeSchema = z.object({
+  id: z.string().min(1),
+  type: z.enum(NODE_TYPES),
+  position: z.object({ x: z.number(), y: z.number() }),
+  data: nodeDataSchema,
+});
+
+export const workflowEdgeSchema = z.object({
+  id: z.string().min(1),
+  source: z.string().min(1),
+  target: z.string().min(1),
+  label: z.string().optional(),
+  condition: z.string().optional(),
+  sourceHandle: z.string().optional(),
+});
+
+export const workflowGraphSchema = z.object({
+  nodes: z.array(workflowNodeSchema),
+  edges: z.array(workflowEdgeSchema),
+});
+
+export const workflowDocumentSchema = z.object({
+  name: z.string().min(1).max(200),
+  description: z.string().max(5000).optional(),
+  graphData: workflowGraphSchema,
+  variables: z.record(z.string(), z.string()).optional(),
+  timeout: z.number().int().min(10).max(86400).nullable().optional(),
+});
+
+const schemas = {
+  workflow: workflowDocumentSchema,
+  graph: workflowGraphSchema,
+  node: workflowNodeSchema,
+  edge: workflowEdgeSchema,
+} as const;
+
+export type SchemaKind = keyof typeof schemas;
+
+export const SCHEMA_KINDS = Object.keys(schemas) as SchemaKind[];
+
+export function isSchemaKind(value: string): value is SchemaKind {
+  return value in schemas;
+}
+
+export function getJsonSchema(kind: SchemaKind): Record<string, unknown> {
+  return {
+    title: `cadre ${kind}`,
+    ...z.toJSONSchema(schemas[kind], {
+      // The refine can't be expressed in JSON Schema, so publish the providers registered right now
+      override: (ctx) => {
+        if (ctx.zodSchema === providerIdSchema) ctx.jsonSchema.enum = listProviders().map(p => p.id);
+      },
+    }),
+  } as Record<string, unknown>;
+}
@@ -5,8 +5,8 @@ export const proxy = auth((req) => {
   const isLoggedIn = !!req.auth;
   const isAuthRoute = req.nextUrl.pathname.startsWith('/api/auth');
   const isLoginPage = req.nextUrl.pathname === '/login';
-  const isHealthCheck = req.nextUrl.pathname === '/api/health';
-  // Allow auth routes, login page, and health check always
+  const isHealthCheck = req.nextUrl.pathname === '/api/health' || req.nextUrl.pathname === '/api/readyz';
+  // Allow auth routes, login page, and health/readiness probes always
   if (isAuthRoute || isLoginPage || isHealthCheck) {
     return NextResponse.next();
   }
@@ -9,6 +9,8 @@ export interface ProviderOptions {
   workspace?: 'off' | 'safe' | 'full';
   maxTurns?: number;
   signal?: AbortSignal;
+  // Resolved secret values in the prompt, masked wherever the request is logged
+  secretValues?: string[];
 }
 
 export interface StreamChunk {
@@ -27,4 +29,5 @@ export const PROVIDERS: ProviderConfig[] = [
   { id: 'claude-code', name: 'Claude Code', color: '#6366f1' },
   { id: 'codex', name: 'Codex', color: '#10a37f' },
   { id: 'gemini', name: 'Gemini', color: '#4285f4' },
+  { id: 'bedrock', name: 'Claude (Bedrock)', color: '#ff9900' },
 ];